@@ -0,0 +1,215 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package geocoding converts GPS coordinates into place names
+// with an offline GeoNames dataset.
+package geocoding
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// earthRadius is the mean radius of the earth in kilometers.
+const earthRadius = 6371.0
+
+// Geocoder finds the nearest place of GPS coordinates.
+type Geocoder struct {
+	// MaxDistance stores the maximum distance to a place in kilometers.
+	MaxDistance float64
+
+	tree *kdTree
+}
+
+// Place stores the names of a place.
+type Place struct {
+	// City stores the name of the city.
+	City string `json:"city"`
+	// Country stores the name of the country.
+	Country string `json:"country"`
+	// CountryCode stores the ISO 3166 code of the country.
+	CountryCode string `json:"country_code"`
+	// Distance stores the distance to the coordinates in kilometers.
+	Distance float64 `json:"distance"`
+	// Latitude stores the latitude of the city.
+	Latitude float64 `json:"latitude"`
+	// Longitude stores the longitude of the city.
+	Longitude float64 `json:"longitude"`
+	// Region stores the name of the region, like a state.
+	Region string `json:"region"`
+}
+
+// Load loads a GeoNames cities file like `cities15000.txt`.
+//
+// Region and country names are read from `admin1CodesASCII.txt` and
+// `countryInfo.txt` in the same folder, if they exist.
+// Otherwise, codes are used instead.
+func Load(citiesFile string, maxDistance float64) (*Geocoder, error) {
+	folder := filepath.Dir(citiesFile)
+
+	countries, err := readNames(filepath.Join(folder, "countryInfo.txt"), 0, 4)
+	if err != nil {
+		return nil, err
+	}
+	regions, err := readNames(filepath.Join(folder, "admin1CodesASCII.txt"), 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(citiesFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	places := make([]*Place, 0)
+	err = readTSV(file, func(fields []string) error {
+		if len(fields) < 11 {
+			return nil
+		}
+
+		latitude, err1 := strconv.ParseFloat(fields[4], 64)
+		longitude, err2 := strconv.ParseFloat(fields[5], 64)
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("invalid coordinates of '%s'", fields[1])
+		}
+
+		countryCode := fields[8]
+
+		place := &Place{
+			City:        fields[1],
+			Country:     countries[countryCode],
+			CountryCode: countryCode,
+			Latitude:    latitude,
+			Longitude:   longitude,
+			Region:      regions[countryCode+"."+fields[10]],
+		}
+		if place.Country == "" {
+			place.Country = countryCode
+		}
+
+		places = append(places, place)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid cities file '%s': %w", citiesFile, err)
+	}
+	if len(places) == 0 {
+		return nil, fmt.Errorf("cities file '%s' contains no places", citiesFile)
+	}
+
+	return &Geocoder{
+		MaxDistance: maxDistance,
+		tree:        newKDTree(places),
+	}, nil
+}
+
+// Lookup returns the nearest place of GPS coordinates or nil,
+// if there is no place within MaxDistance.
+func (g *Geocoder) Lookup(latitude float64, longitude float64) *Place {
+	nearest, chord := g.tree.nearest(toCartesian(latitude, longitude))
+	if nearest == nil {
+		return nil
+	}
+
+	// chord length on the unit sphere to great circle distance
+	distance := 2 * math.Asin(math.Min(1, chord/2)) * earthRadius
+	if g.MaxDistance > 0 && distance > g.MaxDistance {
+		return nil
+	}
+
+	place := *nearest
+	place.Distance = distance
+
+	return &place
+}
+
+// Size returns the number of known places.
+func (g *Geocoder) Size() int {
+	return len(g.tree.nodes)
+}
+
+// readNames reads a map of codes and names from a GeoNames file.
+// Returns an empty map, if the file does not exist.
+func readNames(file string, codeColumn int, nameColumn int) (map[string]string, error) {
+	names := map[string]string{}
+
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	err = readTSV(f, func(fields []string) error {
+		if len(fields) > codeColumn && len(fields) > nameColumn {
+			names[fields[codeColumn]] = fields[nameColumn]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid file '%s': %w", file, err)
+	}
+
+	return names, nil
+}
+
+// readTSV reads the lines of a tab separated file without comments.
+func readTSV(r io.Reader, handle func(fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		err := handle(strings.Split(line, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// toCartesian converts coordinates to a point on the unit sphere,
+// so that distances do not depend on the longitude wrap.
+func toCartesian(latitude float64, longitude float64) [3]float64 {
+	lat := latitude * math.Pi / 180
+	lon := longitude * math.Pi / 180
+
+	return [3]float64{
+		math.Cos(lat) * math.Cos(lon),
+		math.Cos(lat) * math.Sin(lon),
+		math.Sin(lat),
+	}
+}
@@ -0,0 +1,223 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package geocoding
+
+import (
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func randomPlaces(random *rand.Rand, count int, minLatitude, maxLatitude, minLongitude, maxLongitude float64) []*Place {
+	places := make([]*Place, count)
+	for i := range places {
+		places[i] = &Place{
+			Latitude:  minLatitude + random.Float64()*(maxLatitude-minLatitude),
+			Longitude: minLongitude + random.Float64()*(maxLongitude-minLongitude),
+		}
+	}
+
+	return places
+}
+
+func TestKDTreeNearest(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+
+	duplicates := make([]*Place, 20)
+	for i := range duplicates {
+		duplicates[i] = &Place{Latitude: 52.52, Longitude: 13.40}
+	}
+
+	tests := []struct {
+		name   string
+		places []*Place
+	}{
+		{name: "one place", places: randomPlaces(random, 1, -90, 90, -180, 180)},
+		{name: "two places", places: randomPlaces(random, 2, -90, 90, -180, 180)},
+		{name: "world", places: randomPlaces(random, 2000, -90, 90, -180, 180)},
+		{name: "city", places: randomPlaces(random, 500, 52.3, 52.7, 13.1, 13.7)},
+		{name: "poles", places: append(randomPlaces(random, 200, 85, 90, -180, 180), randomPlaces(random, 200, -90, -85, -180, 180)...)},
+		{name: "antimeridian", places: append(randomPlaces(random, 200, -20, 20, 175, 180), randomPlaces(random, 200, -20, 20, -180, -175)...)},
+		{name: "duplicates", places: append(duplicates, randomPlaces(random, 20, 52, 53, 13, 14)...)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := newKDTree(test.places)
+
+			for i := 0; i < 500; i++ {
+				point := toCartesian(random.Float64()*180-90, random.Float64()*360-180)
+				if i%2 == 1 {
+					// near one of the places
+					p := test.places[random.IntN(len(test.places))]
+					point = toCartesian(p.Latitude+random.Float64()*0.1-0.05, p.Longitude+random.Float64()*0.1-0.05)
+				}
+
+				expected := math.Inf(1)
+				for _, p := range test.places {
+					expected = math.Min(expected, squaredDistance(toCartesian(p.Latitude, p.Longitude), point))
+				}
+				expected = math.Sqrt(expected)
+
+				place, distance := tree.nearest(point)
+				if place == nil {
+					t.Fatal("no place found")
+				}
+				if math.Abs(distance-expected) > 1e-12 {
+					t.Fatalf("got distance %v, expected %v", distance, expected)
+				}
+				if actual := math.Sqrt(squaredDistance(toCartesian(place.Latitude, place.Longitude), point)); math.Abs(actual-distance) > 1e-12 {
+					t.Fatalf("place has distance %v, but %v has been returned", actual, distance)
+				}
+			}
+		})
+	}
+}
+
+func TestKDTreeEmpty(t *testing.T) {
+	place, _ := newKDTree(nil).nearest(toCartesian(0, 0))
+	if place != nil {
+		t.Errorf("got place %+v from empty tree", place)
+	}
+}
+
+func writeGeoNamesFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	folder := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(folder, name), []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return filepath.Join(folder, "cities.txt")
+}
+
+// geoNamesLine creates a line of a GeoNames cities file.
+func geoNamesLine(name, latitude, longitude, countryCode, regionCode string) string {
+	fields := make([]string, 19)
+	fields[1] = name
+	fields[4] = latitude
+	fields[5] = longitude
+	fields[8] = countryCode
+	fields[10] = regionCode
+
+	return strings.Join(fields, "\t") + "\n"
+}
+
+func TestLoad(t *testing.T) {
+	cities := "# comment\n\n" +
+		geoNamesLine("Berlin", "52.52437", "13.41053", "DE", "16") +
+		geoNamesLine("Paris", "48.85341", "2.3488", "FR", "11") +
+		"too\tfew\tfields\n"
+
+	tests := []struct {
+		name        string
+		files       map[string]string
+		latitude    float64
+		longitude   float64
+		maxDistance float64
+		expected    *Place
+		expectError bool
+	}{
+		{
+			name: "with names",
+			files: map[string]string{
+				"cities.txt":           cities,
+				"countryInfo.txt":      "# ISO\tISO3\tISO-Numeric\tfips\tCountry\nDE\tDEU\t276\tGM\tGermany\n",
+				"admin1CodesASCII.txt": "DE.16\tBerlin\tBerlin\t2950157\n",
+			},
+			latitude:  52.5,
+			longitude: 13.4,
+			expected:  &Place{City: "Berlin", Country: "Germany", CountryCode: "DE", Latitude: 52.52437, Longitude: 13.41053, Region: "Berlin"},
+		},
+		{
+			name:      "without names",
+			files:     map[string]string{"cities.txt": cities},
+			latitude:  48.9,
+			longitude: 2.3,
+			expected:  &Place{City: "Paris", Country: "FR", CountryCode: "FR", Latitude: 48.85341, Longitude: 2.3488},
+		},
+		{
+			name:        "too far away",
+			files:       map[string]string{"cities.txt": cities},
+			latitude:    40.7,
+			longitude:   -74,
+			maxDistance: 50,
+		},
+		{
+			name:        "invalid coordinates",
+			files:       map[string]string{"cities.txt": geoNamesLine("Nowhere", "north", "13.4", "DE", "16")},
+			expectError: true,
+		},
+		{
+			name:        "no places",
+			files:       map[string]string{"cities.txt": "# comment\n"},
+			expectError: true,
+		},
+		{
+			name:        "missing file",
+			files:       map[string]string{},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			geocoder, err := Load(writeGeoNamesFiles(t, test.files), test.maxDistance)
+			if test.expectError {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			place := geocoder.Lookup(test.latitude, test.longitude)
+			if test.expected == nil {
+				if place != nil {
+					t.Errorf("got %+v, expected no place", place)
+				}
+				return
+			}
+			if place == nil {
+				t.Fatal("no place found")
+			}
+
+			if place.Distance <= 0 || place.Distance > 10 {
+				t.Errorf("got distance %v km", place.Distance)
+			}
+			place.Distance = 0
+			if *place != *test.expected {
+				t.Errorf("got %+v, expected %+v", place, test.expected)
+			}
+		})
+	}
+}
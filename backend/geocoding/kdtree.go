@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package geocoding
+
+import (
+	"math"
+	"sort"
+)
+
+// kdTree is a static 3-dimensional tree for nearest neighbour searches,
+// which is stored in an array like a binary heap.
+type kdTree struct {
+	nodes []kdNode
+	root  int
+}
+
+type kdNode struct {
+	place *Place
+	point [3]float64
+	// index of the children or -1
+	left  int
+	right int
+}
+
+func newKDTree(places []*Place) *kdTree {
+	nodes := make([]kdNode, len(places))
+	for i, p := range places {
+		nodes[i] = kdNode{
+			place: p,
+			point: toCartesian(p.Latitude, p.Longitude),
+		}
+	}
+
+	tree := &kdTree{nodes: nodes}
+	tree.root = tree.build(0, len(nodes), 0)
+
+	return tree
+}
+
+// build sorts nodes[from:to] so that the median is the root of the subtree
+// and returns its index or -1 for an empty range.
+func (t *kdTree) build(from int, to int, depth int) int {
+	if from >= to {
+		return -1
+	}
+
+	axis := depth % 3
+	subtree := t.nodes[from:to]
+	sort.Slice(subtree, func(i, j int) bool {
+		return subtree[i].point[axis] < subtree[j].point[axis]
+	})
+
+	median := from + (to-from)/2
+	t.nodes[median].left = t.build(from, median, depth+1)
+	t.nodes[median].right = t.build(median+1, to, depth+1)
+
+	return median
+}
+
+// nearest returns the place nearest to point and the euclidean distance.
+func (t *kdTree) nearest(point [3]float64) (*Place, float64) {
+	if len(t.nodes) == 0 {
+		return nil, 0
+	}
+
+	best := -1
+	bestDistance := math.Inf(1)
+
+	var search func(index int, depth int)
+	search = func(index int, depth int) {
+		if index < 0 {
+			return
+		}
+
+		node := &t.nodes[index]
+
+		distance := squaredDistance(node.point, point)
+		if distance < bestDistance {
+			best = index
+			bestDistance = distance
+		}
+
+		axis := depth % 3
+		diff := point[axis] - node.point[axis]
+
+		near, far := node.left, node.right
+		if diff > 0 {
+			near, far = far, near
+		}
+
+		search(near, depth+1)
+		// the other side can only contain a nearer point,
+		// if the splitting plane is nearer
+		if diff*diff < bestDistance {
+			search(far, depth+1)
+		}
+	}
+	search(t.root, 0)
+
+	return t.nodes[best].place, math.Sqrt(bestDistance)
+}
+
+func squaredDistance(a [3]float64, b [3]float64) float64 {
+	dx := a[0] - b[0]
+	dy := a[1] - b[1]
+	dz := a[2] - b[2]
+
+	return dx*dx + dy*dy + dz*dz
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package imagehash
+
+// Tree is a BK-tree, which finds all hashes within a Hamming distance
+// without comparing them with every hash.
+type Tree[T any] struct {
+	root *treeNode[T]
+	size int
+}
+
+// Match is a result of Tree.Search().
+type Match[T any] struct {
+	// Distance stores the Hamming distance to the searched hash.
+	Distance int
+	// Hash stores the found hash.
+	Hash Hash
+	// Value stores the value of the hash.
+	Value T
+}
+
+type treeNode[T any] struct {
+	// children by their distance to this node
+	children map[int]*treeNode[T]
+	hash     Hash
+	value    T
+}
+
+// Add adds a hash with a value. Equal hashes can be added more than once.
+func (t *Tree[T]) Add(hash Hash, value T) {
+	t.size++
+
+	newNode := &treeNode[T]{hash: hash, value: value}
+	if t.root == nil {
+		t.root = newNode
+		return
+	}
+
+	node := t.root
+	for {
+		distance := node.hash.Distance(hash)
+
+		child, ok := node.children[distance]
+		if !ok {
+			if node.children == nil {
+				node.children = map[int]*treeNode[T]{}
+			}
+			node.children[distance] = newNode
+			return
+		}
+
+		node = child
+	}
+}
+
+// Search returns all entries with a distance to hash up to maxDistance.
+func (t *Tree[T]) Search(hash Hash, maxDistance int) []Match[T] {
+	matches := make([]Match[T], 0)
+	if t.root == nil {
+		return matches
+	}
+
+	stack := []*treeNode[T]{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		distance := node.hash.Distance(hash)
+		if distance <= maxDistance {
+			matches = append(matches, Match[T]{Distance: distance, Hash: node.hash, Value: node.value})
+		}
+
+		// triangle inequality: only children in this range can match
+		for childDistance, child := range node.children {
+			if childDistance >= distance-maxDistance && childDistance <= distance+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	return matches
+}
+
+// Size returns the number of entries.
+func (t *Tree[T]) Size() int {
+	return t.size
+}
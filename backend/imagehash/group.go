@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package imagehash
+
+import "sort"
+
+// Group returns the indexes of hashes, which are connected by pairs with
+// a distance up to maxDistance, like 1, 2 and 3, if 1 is similar to 2 and
+// 2 is similar to 3. Only groups with more than one hash are returned,
+// each sorted ascending and ordered by their first index.
+func Group(hashes []Hash, maxDistance int) [][]int {
+	tree := &Tree[int]{}
+	for i, hash := range hashes {
+		tree.Add(hash, i)
+	}
+
+	// union-find of the indexes
+	parents := make([]int, len(hashes))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	for i, hash := range hashes {
+		for _, match := range tree.Search(hash, maxDistance) {
+			a, b := find(i), find(match.Value)
+			if a != b {
+				parents[b] = a
+			}
+		}
+	}
+
+	groupsByRoot := map[int][]int{}
+	for i := range hashes {
+		root := find(i)
+		groupsByRoot[root] = append(groupsByRoot[root], i)
+	}
+
+	groups := make([][]int, 0)
+	for _, group := range groupsByRoot {
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+
+	return groups
+}
@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package imagehash computes perceptual hashes of images, which are
+// similar for visually similar images, like copies in different sizes.
+package imagehash
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math/bits"
+	"strconv"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Hash is a 64 bit perceptual hash.
+type Hash uint64
+
+// Compute decodes an image and returns its difference hash.
+func Compute(r io.Reader) (Hash, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return DHash(img)
+}
+
+// DHash returns the difference hash of an image: it is scaled down to 9x8
+// gray pixels and each bit tells, if a pixel is brighter than its right neighbour.
+func DHash(img image.Image) (Hash, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() < 1 || bounds.Dy() < 1 {
+		return 0, fmt.Errorf("image has no pixels")
+	}
+
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.BiLinear.Scale(small, small.Bounds(), img, bounds, draw.Src, nil)
+
+	var hash Hash
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if small.GrayAt(x, y).Y > small.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, nil
+}
+
+// Parse parses a hash in the format of Hash.String().
+func Parse(value string) (Hash, error) {
+	if len(value) != 16 {
+		return 0, fmt.Errorf("invalid hash '%s'", value)
+	}
+
+	hash, err := strconv.ParseUint(value, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hash '%s'", value)
+	}
+
+	return Hash(hash), nil
+}
+
+// Distance returns the Hamming distance between two hashes, which is
+// the number of different bits: 0 for equal and 64 for inverse hashes.
+func (h Hash) Distance(other Hash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// String returns the hash as 16 hexadecimal digits.
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
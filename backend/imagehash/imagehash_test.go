@@ -0,0 +1,246 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// randomHashes returns count hashes, which are near to some centers,
+// so that there are duplicates and hashes with small distances.
+func randomHashes(random *rand.Rand, count int, centers int, maxFlips int) []Hash {
+	centerHashes := make([]Hash, centers)
+	for i := range centerHashes {
+		centerHashes[i] = Hash(random.Uint64())
+	}
+
+	hashes := make([]Hash, count)
+	for i := range hashes {
+		hash := centerHashes[random.IntN(centers)]
+		for flips := random.IntN(maxFlips + 1); flips > 0; flips-- {
+			hash ^= 1 << random.IntN(64)
+		}
+		hashes[i] = hash
+	}
+
+	return hashes
+}
+
+func TestTreeSearch(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+
+	tests := []struct {
+		name        string
+		hashes      []Hash
+		maxDistance int
+	}{
+		{name: "empty", hashes: []Hash{}, maxDistance: 10},
+		{name: "equal hashes", hashes: []Hash{1, 1, 1, 1}, maxDistance: 0},
+		{name: "random", hashes: randomHashes(random, 1000, 1000, 0), maxDistance: 20},
+		{name: "clusters", hashes: randomHashes(random, 1000, 20, 8), maxDistance: 5},
+		{name: "clusters exact", hashes: randomHashes(random, 1000, 20, 2), maxDistance: 0},
+		{name: "everything", hashes: randomHashes(random, 200, 5, 30), maxDistance: 64},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := &Tree[int]{}
+			for i, hash := range test.hashes {
+				tree.Add(hash, i)
+			}
+			if tree.Size() != len(test.hashes) {
+				t.Errorf("got size %d, expected %d", tree.Size(), len(test.hashes))
+			}
+
+			queries := append(randomHashes(random, 50, 50, 0), test.hashes...)
+			for _, query := range queries {
+				expected := make([]int, 0)
+				for i, hash := range test.hashes {
+					if hash.Distance(query) <= test.maxDistance {
+						expected = append(expected, i)
+					}
+				}
+
+				actual := make([]int, 0)
+				for _, match := range tree.Search(query, test.maxDistance) {
+					if match.Distance != match.Hash.Distance(query) || match.Hash != test.hashes[match.Value] {
+						t.Fatalf("invalid match %+v", match)
+					}
+					actual = append(actual, match.Value)
+				}
+				sort.Ints(actual)
+
+				if !reflect.DeepEqual(actual, expected) {
+					t.Fatalf("got %v for %v, expected %v", actual, query, expected)
+				}
+			}
+		})
+	}
+}
+
+// groupBruteForce returns the connected components of hashes by
+// a breadth-first search, which compares each pair of hashes.
+func groupBruteForce(hashes []Hash, maxDistance int) [][]int {
+	visited := make([]bool, len(hashes))
+
+	groups := make([][]int, 0)
+	for i := range hashes {
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+
+		group := []int{i}
+		for next := 0; next < len(group); next++ {
+			for j := range hashes {
+				if !visited[j] && hashes[group[next]].Distance(hashes[j]) <= maxDistance {
+					visited[j] = true
+					group = append(group, j)
+				}
+			}
+		}
+
+		if len(group) > 1 {
+			sort.Ints(group)
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+func TestGroup(t *testing.T) {
+	random := rand.New(rand.NewPCG(3, 4))
+
+	tests := []struct {
+		name        string
+		hashes      []Hash
+		maxDistance int
+		expected    [][]int
+	}{
+		{name: "empty", hashes: []Hash{}, maxDistance: 10, expected: [][]int{}},
+		{name: "no duplicates", hashes: []Hash{0, 0xFF, 0xFFFF}, maxDistance: 4, expected: [][]int{}},
+		// 0 and 2 are only connected by 1
+		{name: "chain", hashes: []Hash{0b0000, 0b1111_0000, 0xFF, 0xFFFF_0000_0000}, maxDistance: 4, expected: [][]int{{0, 1, 2}}},
+		{name: "random", hashes: randomHashes(random, 500, 500, 0), maxDistance: 20},
+		{name: "clusters", hashes: randomHashes(random, 500, 30, 10), maxDistance: 6},
+		{name: "clusters exact", hashes: randomHashes(random, 500, 100, 1), maxDistance: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := test.expected
+			if expected == nil {
+				expected = groupBruteForce(test.hashes, test.maxDistance)
+			}
+
+			actual := Group(test.hashes, test.maxDistance)
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("got %v, expected %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    Hash
+		expectError bool
+	}{
+		{value: "0000000000000000", expected: 0},
+		{value: "ffffffffffffffff", expected: 0xFFFFFFFFFFFFFFFF},
+		{value: "0123456789ABCDEF", expected: 0x0123456789ABCDEF},
+		{value: "123", expectError: true},
+		{value: "0123456789abcdef0", expectError: true},
+		{value: "0123456789abcdeg", expectError: true},
+		{value: "-123456789abcdef", expectError: true},
+	}
+
+	for _, test := range tests {
+		hash, err := Parse(test.value)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("%q: expected error, got %v", test.value, hash)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.value, err)
+			continue
+		}
+
+		if hash != test.expected {
+			t.Errorf("%q: got %v, expected %v", test.value, hash, test.expected)
+		}
+		if parsed, _ := Parse(hash.String()); parsed != hash {
+			t.Errorf("%q: got %v after String(), expected %v", test.value, parsed, hash)
+		}
+	}
+}
+
+func TestDHash(t *testing.T) {
+	gradient := func(width int, height int, inverse bool) image.Image {
+		img := image.NewGray(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				value := uint8(x * 255 / width)
+				if inverse {
+					value = 255 - value
+				}
+				img.SetGray(x, y, color.Gray{Y: value})
+			}
+		}
+		return img
+	}
+
+	small, err := DHash(gradient(90, 80, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := DHash(gradient(900, 800, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inverse, err := DHash(gradient(900, 800, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if distance := small.Distance(large); distance > 2 {
+		t.Errorf("scaled image has distance %d", distance)
+	}
+	if distance := large.Distance(inverse); distance < 60 {
+		t.Errorf("inverse image has distance %d", distance)
+	}
+
+	_, err = DHash(image.NewGray(image.Rect(0, 0, 0, 0)))
+	if err == nil {
+		t.Error("expected error for image without pixels")
+	}
+}
@@ -23,32 +23,155 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
 
 	"github.com/gorilla/mux"
+	"github.com/mkloubert/my-ai-gallery/geocoding"
+	"github.com/mkloubert/my-ai-gallery/providers"
 	"github.com/mkloubert/my-ai-gallery/routes"
 	"github.com/mkloubert/my-ai-gallery/types"
 )
 
 func main() {
+	flagSet := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	pendingMigrations := flagSet.Bool("pending-migrations", false, "print pending database migrations and exit")
+	configFlags := types.RegisterConfigFlags(flagSet)
+	flagSet.Parse(os.Args[1:])
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		panic(err)
 	}
 
+	config, err := types.LoadAppConfig(cwd, configFlags)
+	if err != nil {
+		panic(err)
+	}
+
+	visionProvider, err := providers.NewVisionProvider(&config.Vision)
+	if err != nil {
+		panic(err)
+	}
+
+	var embeddingProvider types.EmbeddingProvider
+	if config.Embeddings.Enabled {
+		embeddingProvider, err = providers.NewEmbeddingProvider(&config.Embeddings)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var geocoder *geocoding.Geocoder
+	if config.Geocoding.CitiesFile != "" {
+		geocoder, err = geocoding.Load(config.Geocoding.CitiesFile, config.Geocoding.MaxDistance)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	app := &types.AppContext{
-		EOL:              fmt.Sprintln(),
-		Stderr:           os.Stderr,
-		Stdout:           os.Stdout,
-		WorkingDirectory: cwd,
+		Config:            config,
+		EmbeddingProvider: embeddingProvider,
+		EOL:               fmt.Sprintln(),
+		Geocoder:          geocoder,
+		Stderr:            os.Stderr,
+		Stdout:            os.Stdout,
+		VisionProvider:    visionProvider,
+		WorkingDirectory:  cwd,
+	}
+
+	if app.Geocoder != nil {
+		fmt.Fprintf(app.Stdout, "Loaded %d places for reverse geocoding%s", app.Geocoder.Size(), app.EOL)
+	}
+
+	if *pendingMigrations {
+		printPendingMigrations(app)
+		return
+	}
+
+	err = app.MigrateImageDatabase()
+	if err != nil {
+		panic(err)
+	}
+
+	app.Jobs = types.NewJobQueue(app, config.Jobs.Workers)
+	err = app.Jobs.Start()
+	if err != nil {
+		panic(err)
+	}
+
+	app.StartUploadCleanup()
+
+	if config.Watcher.Enabled {
+		// also indexes the images
+		err = types.NewFileWatcher(app).Start()
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		go func() {
+			_, err := app.IndexImages()
+			if err != nil {
+				fmt.Fprintf(app.Stderr, "[ERROR] Indexing images failed: %s%s", err.Error(), app.EOL)
+			}
+		}()
 	}
 
 	r := mux.NewRouter()
+	r.HandleFunc("/api/duplicates", routes.CreateGetDuplicatesHandler(app)).Methods("GET")
+	r.HandleFunc("/api/folders", routes.CreateGetFoldersHandler(app)).Methods("GET")
+	r.HandleFunc("/api/geo", routes.CreateGetGeoHandler(app)).Methods("GET")
 	r.HandleFunc("/api/images", routes.CreateGetImagesHandler(app)).Methods("GET")
-	r.HandleFunc("/api/images/{imagename}", routes.CreateGetImageHandler(app)).Methods("GET")
-	r.HandleFunc("/api/images/{imagename}/meta", routes.CreateUpdateImageMetaHandler(app)).Methods("PATCH")
+	r.HandleFunc("/api/images", routes.CreateUploadImagesHandler(app)).Methods("POST")
+	r.HandleFunc("/api/images/retag", routes.CreateRetagImagesHandler(app)).Methods("POST")
+	r.HandleFunc("/api/images/{imagename:.+}/locks", routes.CreateSetImageMetaLocksHandler(app)).Methods("PUT")
+	r.HandleFunc("/api/images/{imagename:.+}/meta", routes.CreateUpdateImageMetaHandler(app)).Methods("PATCH")
+	r.HandleFunc("/api/images/{imagename:.+}/meta", routes.CreateSetImageMetaHandler(app)).Methods("PUT")
+	r.HandleFunc("/api/images/{imagename:.+}/move", routes.CreateMoveImageHandler(app)).Methods("POST")
+	r.HandleFunc("/api/images/{imagename:.+}/preferred", routes.CreateSetPreferredImageHandler(app)).Methods("PUT")
+	r.HandleFunc("/api/images/{imagename:.+}/similar", routes.CreateGetSimilarImagesHandler(app)).Methods("GET")
+	r.HandleFunc("/api/images/{imagename:.+}/thumb", routes.CreateGetImageThumbnailHandler(app)).Methods("GET", "HEAD")
+	r.HandleFunc("/api/images/{imagename:.+}", routes.CreateGetImageHandler(app)).Methods("GET", "HEAD")
+	r.HandleFunc("/api/images/{imagename:.+}", routes.CreateDeleteImageHandler(app)).Methods("DELETE")
+	r.HandleFunc("/api/jobs", routes.CreateGetJobsHandler(app)).Methods("GET")
+	r.HandleFunc("/api/jobs", routes.CreateStartJobHandler(app)).Methods("POST")
+	r.HandleFunc("/api/jobs/{id}", routes.CreateGetJobHandler(app)).Methods("GET")
+	r.HandleFunc("/api/search", routes.CreateSearchHandler(app)).Methods("GET")
+	r.HandleFunc("/api/search/semantic", routes.CreateSemanticSearchHandler(app)).Methods("GET")
+	r.HandleFunc("/api/tags", routes.CreateGetTagsHandler(app)).Methods("GET")
+	r.HandleFunc("/api/tags/{tag:.+}/images", routes.CreateGetTagImagesHandler(app)).Methods("GET")
+	r.HandleFunc("/api/uploads", routes.CreateGetUploadOptionsHandler(app)).Methods("OPTIONS")
+	r.HandleFunc("/api/uploads", routes.CreateCreateUploadHandler(app)).Methods("POST")
+	r.HandleFunc("/api/uploads/{id}", routes.CreateDeleteUploadHandler(app)).Methods("DELETE")
+	r.HandleFunc("/api/uploads/{id}", routes.CreateGetUploadHandler(app)).Methods("GET", "HEAD")
+	r.HandleFunc("/api/uploads/{id}", routes.CreatePatchUploadHandler(app)).Methods("PATCH")
+
+	fmt.Fprintf(app.Stdout, "Listening on '%s' ...%s", config.Listen, app.EOL)
 
-	http.ListenAndServe(":8080", r)
+	http.ListenAndServe(config.Listen, r)
+}
+
+func printPendingMigrations(app *types.AppContext) {
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	migrations, err := app.GetPendingMigrations(db)
+	if err != nil {
+		panic(err)
+	}
+
+	if len(migrations) == 0 {
+		fmt.Fprintf(app.Stdout, "No pending migrations%s", app.EOL)
+		return
+	}
+
+	for _, m := range migrations {
+		fmt.Fprintf(app.Stdout, "%04d_%s%s", m.Version, m.Name, app.EOL)
+	}
 }
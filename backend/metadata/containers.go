@@ -0,0 +1,244 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metadata
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// maxSegmentSize is the maximum size of a metadata block, which is loaded.
+const maxSegmentSize = 16 * 1024 * 1024
+
+var (
+	exifHeader   = []byte("Exif\x00\x00")
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	xmpHeader    = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	xmpKeyword   = "XML:com.adobe.xmp"
+)
+
+// readJPEG reads the EXIF and XMP data from the APP1 segments of a JPEG file.
+func readJPEG(r io.ReadSeeker) ([]byte, []byte, error) {
+	var exifData, xmpData []byte
+
+	_, err := r.Seek(2, io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	marker := make([]byte, 4)
+	for {
+		_, err = io.ReadFull(r, marker[:2])
+		if err != nil {
+			break
+		}
+		if marker[0] != 0xFF {
+			return nil, nil, fmt.Errorf("invalid JPEG marker")
+		}
+
+		// fill bytes
+		for marker[1] == 0xFF {
+			_, err = io.ReadFull(r, marker[1:2])
+			if err != nil {
+				return exifData, xmpData, nil
+			}
+		}
+
+		// markers without length
+		if marker[1] == 0x01 || (marker[1] >= 0xD0 && marker[1] <= 0xD7) {
+			continue
+		}
+		// start of scan or end of image: no more metadata
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			break
+		}
+
+		_, err = io.ReadFull(r, marker[2:4])
+		if err != nil {
+			break
+		}
+		length := int64(binary.BigEndian.Uint16(marker[2:4])) - 2
+		if length < 0 {
+			return nil, nil, fmt.Errorf("invalid JPEG segment length")
+		}
+
+		if marker[1] != 0xE1 {
+			_, err = r.Seek(length, io.SeekCurrent)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		segment := make([]byte, length)
+		_, err = io.ReadFull(r, segment)
+		if err != nil {
+			break
+		}
+
+		if exifData == nil && bytes.HasPrefix(segment, exifHeader) {
+			exifData = segment[len(exifHeader):]
+		} else if xmpData == nil && bytes.HasPrefix(segment, xmpHeader) {
+			xmpData = segment[len(xmpHeader):]
+		}
+	}
+
+	return exifData, xmpData, nil
+}
+
+// readPNG reads the EXIF data from the `eXIf` chunk
+// and XMP from the `iTXt` chunk of a PNG file.
+func readPNG(r io.ReadSeeker) ([]byte, []byte, error) {
+	var exifData, xmpData []byte
+
+	_, err := r.Seek(int64(len(pngSignature)), io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := make([]byte, 8)
+	for {
+		_, err = io.ReadFull(r, header)
+		if err != nil {
+			break
+		}
+
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		chunkType := string(header[4:8])
+
+		if chunkType == "IEND" {
+			break
+		}
+
+		if (chunkType == "eXIf" || chunkType == "iTXt") && length <= maxSegmentSize {
+			data := make([]byte, length)
+			_, err = io.ReadFull(r, data)
+			if err != nil {
+				break
+			}
+
+			if chunkType == "eXIf" && exifData == nil {
+				exifData = data
+			} else if chunkType == "iTXt" && xmpData == nil {
+				xmpData = readPNGXMP(data)
+			}
+
+			length = 0
+		}
+
+		// data and CRC
+		_, err = r.Seek(length+4, io.SeekCurrent)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return exifData, xmpData, nil
+}
+
+// readPNGXMP returns the XMP of an `iTXt` chunk or nil,
+// if it contains other text.
+func readPNGXMP(data []byte) []byte {
+	keyword, rest, ok := bytes.Cut(data, []byte{0})
+	if !ok || string(keyword) != xmpKeyword || len(rest) < 2 {
+		return nil
+	}
+
+	compressed := rest[0] == 1
+	rest = rest[2:]
+
+	// language tag and translated keyword
+	for i := 0; i < 2; i++ {
+		_, rest, ok = bytes.Cut(rest, []byte{0})
+		if !ok {
+			return nil
+		}
+	}
+
+	if !compressed {
+		return rest
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(rest))
+	if err != nil {
+		return nil
+	}
+	defer reader.Close()
+
+	text, err := io.ReadAll(io.LimitReader(reader, maxSegmentSize))
+	if err != nil {
+		return nil
+	}
+
+	return text
+}
+
+// readWebP reads the EXIF and XMP data from the `EXIF`
+// and `XMP ` chunks of a WebP file.
+func readWebP(r io.ReadSeeker) ([]byte, []byte, error) {
+	var exifData, xmpData []byte
+
+	_, err := r.Seek(12, io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := make([]byte, 8)
+	for {
+		_, err = io.ReadFull(r, header)
+		if err != nil {
+			break
+		}
+
+		chunkType := string(header[:4])
+		length := int64(binary.LittleEndian.Uint32(header[4:8]))
+		// chunks are padded to an even size
+		padding := length % 2
+
+		if (chunkType == "EXIF" || chunkType == "XMP ") && length <= maxSegmentSize {
+			data := make([]byte, length)
+			_, err = io.ReadFull(r, data)
+			if err != nil {
+				break
+			}
+
+			if chunkType == "EXIF" {
+				exifData = data
+			} else {
+				xmpData = data
+			}
+
+			length = 0
+		}
+
+		_, err = r.Seek(length+padding, io.SeekCurrent)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return exifData, xmpData, nil
+}
@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package metadata extracts EXIF and XMP metadata from image files.
+package metadata
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// ErrUnsupportedFormat is returned if the format of a file is not supported.
+var ErrUnsupportedFormat = errors.New("unsupported file format")
+
+// GPSPosition stores a geographic position.
+type GPSPosition struct {
+	// Altitude stores the altitude in meters above sea level, if known.
+	Altitude *float64 `json:"altitude,omitempty"`
+	// Latitude stores the latitude in degrees, negative for south.
+	Latitude float64 `json:"latitude"`
+	// Longitude stores the longitude in degrees, negative for west.
+	Longitude float64 `json:"longitude"`
+}
+
+// Metadata stores the metadata of an image file.
+type Metadata struct {
+	// CameraMake stores the manufacturer of the camera.
+	CameraMake string `json:"camera_make,omitempty"`
+	// CameraModel stores the model of the camera.
+	CameraModel string `json:"camera_model,omitempty"`
+	// ExposureTime stores the exposure time in seconds.
+	ExposureTime *float64 `json:"exposure_time,omitempty"`
+	// FNumber stores the f-number.
+	FNumber *float64 `json:"f_number,omitempty"`
+	// FocalLength stores the focal length in millimeters.
+	FocalLength *float64 `json:"focal_length,omitempty"`
+	// GPS stores the position, where the image has been taken.
+	GPS *GPSPosition `json:"gps,omitempty"`
+	// ISO stores the ISO speed.
+	ISO *int `json:"iso,omitempty"`
+	// LensModel stores the model of the lens.
+	LensModel string `json:"lens_model,omitempty"`
+	// Orientation stores the EXIF orientation from 1 to 8.
+	Orientation *int `json:"orientation,omitempty"`
+	// TakenAt stores the time, when the image has been taken, in
+	// `2006-01-02T15:04:05` format, followed by the time zone offset, if known.
+	TakenAt string `json:"taken_at,omitempty"`
+}
+
+// Read reads the metadata from an image file in JPEG, TIFF, PNG or WebP format.
+// Returns empty metadata, if the file does not contain any.
+func Read(r io.ReadSeeker) (*Metadata, error) {
+	header := make([]byte, 12)
+	n, err := io.ReadFull(r, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	header = header[:n]
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	var exifData, xmpData []byte
+	switch {
+	case bytes.HasPrefix(header, []byte{0xFF, 0xD8}):
+		exifData, xmpData, err = readJPEG(r)
+	case bytes.HasPrefix(header, []byte("II*\x00")) || bytes.HasPrefix(header, []byte("MM\x00*")):
+		return readTIFFFile(r)
+	case bytes.HasPrefix(header, pngSignature):
+		exifData, xmpData, err = readPNG(r)
+	case len(header) == 12 && bytes.HasPrefix(header, []byte("RIFF")) && bytes.Equal(header[8:], []byte("WEBP")):
+		exifData, xmpData, err = readWebP(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{}
+
+	if len(exifData) > 0 {
+		// some writers keep the JPEG prefix
+		exifData = bytes.TrimPrefix(exifData, exifHeader)
+
+		err = parseTIFF(bytes.NewReader(exifData), int64(len(exifData)), meta)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(xmpData) > 0 {
+		// XMP only completes missing values
+		parseXMP(xmpData, meta)
+	}
+
+	return meta, nil
+}
+
+func readTIFFFile(r io.ReadSeeker) (*Metadata, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	readerAt, ok := r.(io.ReaderAt)
+	if !ok {
+		_, err = r.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		readerAt = bytes.NewReader(data)
+	}
+
+	meta := &Metadata{}
+	err = parseTIFF(readerAt, size, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
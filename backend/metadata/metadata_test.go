@@ -0,0 +1,511 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type testField struct {
+	count     uint32
+	data      []byte
+	fieldType uint16
+	tag       uint16
+}
+
+func asciiField(tag uint16, value string) testField {
+	return testField{count: uint32(len(value) + 1), data: []byte(value + "\x00"), fieldType: 2, tag: tag}
+}
+
+func longField(tag uint16, value uint32) testField {
+	return testField{count: 1, data: binary.LittleEndian.AppendUint32(nil, value), fieldType: 4, tag: tag}
+}
+
+func rationalField(tag uint16, values ...uint32) testField {
+	data := make([]byte, 0)
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, v)
+	}
+
+	return testField{count: uint32(len(values) / 2), data: data, fieldType: 5, tag: tag}
+}
+
+func shortField(tag uint16, value uint16) testField {
+	return testField{count: 1, data: binary.LittleEndian.AppendUint16(nil, value), fieldType: 3, tag: tag}
+}
+
+// buildTIFF creates a little endian TIFF structure with IFD0 and
+// the optional EXIF and GPS IFDs, which are linked from IFD0.
+func buildTIFF(ifd0 []testField, exif []testField, gps []testField) []byte {
+	ifdSize := func(fields []testField) int {
+		size := 2 + len(fields)*12 + 4
+		for _, f := range fields {
+			if len(f.data) > 4 {
+				size += len(f.data)
+			}
+		}
+		return size
+	}
+
+	fields := append([]testField{}, ifd0...)
+	if exif != nil {
+		fields = append(fields, longField(tagExifIFD, 0))
+	}
+	if gps != nil {
+		fields = append(fields, longField(tagGPSIFD, 0))
+	}
+
+	exifOffset := 8 + ifdSize(fields)
+	gpsOffset := exifOffset + ifdSize(exif)
+	for i := range fields {
+		switch fields[i].tag {
+		case tagExifIFD:
+			fields[i] = longField(tagExifIFD, uint32(exifOffset))
+		case tagGPSIFD:
+			fields[i] = longField(tagGPSIFD, uint32(gpsOffset))
+		}
+	}
+
+	data := []byte("II*\x00")
+	data = binary.LittleEndian.AppendUint32(data, 8)
+
+	for _, ifd := range [][]testField{fields, exif, gps} {
+		if ifd == nil {
+			continue
+		}
+
+		valueOffset := len(data) + 2 + len(ifd)*12 + 4
+		values := make([]byte, 0)
+
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(ifd)))
+		for _, f := range ifd {
+			data = binary.LittleEndian.AppendUint16(data, f.tag)
+			data = binary.LittleEndian.AppendUint16(data, f.fieldType)
+			data = binary.LittleEndian.AppendUint32(data, f.count)
+
+			if len(f.data) > 4 {
+				data = binary.LittleEndian.AppendUint32(data, uint32(valueOffset+len(values)))
+				values = append(values, f.data...)
+			} else {
+				inline := make([]byte, 4)
+				copy(inline, f.data)
+				data = append(data, inline...)
+			}
+		}
+		// no next IFD
+		data = binary.LittleEndian.AppendUint32(data, 0)
+		data = append(data, values...)
+	}
+
+	return data
+}
+
+// buildJPEG creates a JPEG file, which contains APP1 segments and no image.
+func buildJPEG(segments ...[]byte) []byte {
+	data := []byte{0xFF, 0xD8}
+	for _, segment := range segments {
+		data = append(data, 0xFF, 0xE1)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(segment)+2))
+		data = append(data, segment...)
+	}
+
+	return append(data, 0xFF, 0xD9)
+}
+
+// buildPNG creates a PNG file, which contains chunks without CRC check and no image.
+func buildPNG(chunks ...string) []byte {
+	data := append([]byte{}, pngSignature...)
+	for i := 0; i+1 < len(chunks); i += 2 {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(chunks[i+1])))
+		data = append(data, chunks[i]...)
+		data = append(data, chunks[i+1]...)
+		data = append(data, 0, 0, 0, 0)
+	}
+
+	return append(data, "\x00\x00\x00\x00IEND\x00\x00\x00\x00"...)
+}
+
+// buildWebP creates a WebP file, which contains chunks and no image.
+func buildWebP(chunks ...string) []byte {
+	data := make([]byte, 0)
+	for i := 0; i+1 < len(chunks); i += 2 {
+		data = append(data, chunks[i]...)
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(chunks[i+1])))
+		data = append(data, chunks[i+1]...)
+		if len(chunks[i+1])%2 == 1 {
+			data = append(data, 0)
+		}
+	}
+
+	header := []byte("RIFF")
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(data)+4))
+	header = append(header, "WEBP"...)
+
+	return append(header, data...)
+}
+
+func testTIFF() []byte {
+	return buildTIFF(
+		[]testField{
+			asciiField(tagMake, "Canon"),
+			asciiField(tagModel, "EOS R5"),
+			shortField(tagOrientation, 6),
+		},
+		[]testField{
+			asciiField(tagDateTimeOriginal, "2024:07:01 14:03:22"),
+			asciiField(tagOffsetTimeOriginal, "+02:00"),
+			rationalField(tagFNumber, 4, 1),
+			shortField(tagISOSpeedRatings, 100),
+		},
+		[]testField{
+			asciiField(tagGPSLatitudeRef, "N"),
+			rationalField(tagGPSLatitude, 52, 1, 30, 1, 0, 1),
+			asciiField(tagGPSLongitudeRef, "W"),
+			rationalField(tagGPSLongitude, 13, 1, 24, 1, 0, 1),
+		},
+	)
+}
+
+func testMetadata() *Metadata {
+	fNumber := 4.0
+	iso := 100
+	orientation := 6
+
+	return &Metadata{
+		CameraMake:  "Canon",
+		CameraModel: "EOS R5",
+		FNumber:     &fNumber,
+		GPS:         &GPSPosition{Latitude: 52.5, Longitude: -13.4},
+		ISO:         &iso,
+		Orientation: &orientation,
+		TakenAt:     "2024-07-01T14:03:22+02:00",
+	}
+}
+
+func TestRead(t *testing.T) {
+	tiff := testTIFF()
+
+	tests := []struct {
+		name        string
+		data        []byte
+		expected    *Metadata
+		expectError bool
+	}{
+		{
+			name:     "TIFF",
+			data:     tiff,
+			expected: testMetadata(),
+		},
+		{
+			name:     "JPEG",
+			data:     buildJPEG(append([]byte("Exif\x00\x00"), tiff...)),
+			expected: testMetadata(),
+		},
+		{
+			name:     "PNG",
+			data:     buildPNG("tEXt", "Comment\x00test", "eXIf", string(tiff)),
+			expected: testMetadata(),
+		},
+		{
+			name: "PNG with too large chunk",
+			data: append(
+				buildPNG("eXIf", string(tiff))[:len(pngSignature)],
+				0x7F, 0xFF, 0xFF, 0xFF, 'e', 'X', 'I', 'f',
+			),
+			expected: &Metadata{},
+		},
+		{
+			name: "WebP",
+			data: buildWebP("VP8 ", "x", "EXIF", string(tiff), "XMP ", `<rdf:Description aux:Lens="RF24-105mm F4 L IS USM"/>`),
+			expected: func() *Metadata {
+				meta := testMetadata()
+				meta.LensModel = "RF24-105mm F4 L IS USM"
+				return meta
+			}(),
+		},
+		{
+			name:     "JPEG without metadata",
+			data:     buildJPEG(),
+			expected: &Metadata{},
+		},
+		{
+			name:     "JPEG with truncated segment",
+			data:     buildJPEG(append([]byte("Exif\x00\x00"), tiff...))[:40],
+			expected: &Metadata{},
+		},
+		{
+			name:        "JPEG with invalid marker",
+			data:        []byte{0xFF, 0xD8, 0x00, 0xE1},
+			expectError: true,
+		},
+		{
+			name:        "JPEG with invalid segment length",
+			data:        []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x01},
+			expectError: true,
+		},
+		{
+			name:        "JPEG with too short EXIF",
+			data:        buildJPEG([]byte("Exif\x00\x00II*")),
+			expectError: true,
+		},
+		{
+			name:        "TIFF with invalid magic number",
+			data:        []byte("II+\x00\x08\x00\x00\x00"),
+			expectError: true,
+		},
+		{
+			name:        "unsupported format",
+			data:        []byte("GIF89a"),
+			expectError: true,
+		},
+		{
+			name:        "empty file",
+			data:        []byte{},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta, err := Read(bytes.NewReader(test.data))
+			if test.expectError {
+				if err == nil {
+					t.Errorf("expected error, got %+v", meta)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(normalizeMetadata(meta), normalizeMetadata(test.expected)) {
+				t.Errorf("got %+v, expected %+v", meta, test.expected)
+			}
+		})
+	}
+}
+
+// normalizeMetadata rounds GPS coordinates, so they can be compared.
+func normalizeMetadata(meta *Metadata) *Metadata {
+	if meta == nil || meta.GPS == nil {
+		return meta
+	}
+
+	result := *meta
+	result.GPS = &GPSPosition{
+		Altitude:  meta.GPS.Altitude,
+		Latitude:  math.Round(meta.GPS.Latitude*1e6) / 1e6,
+		Longitude: math.Round(meta.GPS.Longitude*1e6) / 1e6,
+	}
+
+	return &result
+}
+
+func TestReadTruncated(t *testing.T) {
+	tiff := testTIFF()
+	jpeg := buildJPEG(append([]byte("Exif\x00\x00"), tiff...))
+
+	// every prefix of a file and of its EXIF data must be read without panic
+	for n := 0; n <= len(jpeg); n++ {
+		Read(bytes.NewReader(jpeg[:n]))
+	}
+	for n := 0; n <= len(tiff); n++ {
+		Read(bytes.NewReader(tiff[:n]))
+		Read(bytes.NewReader(buildJPEG(append([]byte("Exif\x00\x00"), tiff[:n]...))))
+		Read(bytes.NewReader(buildPNG("eXIf", string(tiff[:n]))))
+		Read(bytes.NewReader(buildWebP("EXIF", string(tiff[:n]))))
+	}
+}
+
+func TestParseTIFF(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *Metadata
+	}{
+		{
+			name:     "IFD outside of data",
+			data:     append([]byte("II*\x00"), 0xFF, 0xFF, 0x00, 0x00),
+			expected: &Metadata{},
+		},
+		{
+			name: "EXIF IFD, which points to IFD0",
+			data: buildTIFF(
+				[]testField{asciiField(tagMake, "Canon"), longField(tagExifIFD, 8)},
+				nil, nil,
+			),
+			expected: &Metadata{CameraMake: "Canon"},
+		},
+		{
+			name: "value outside of data",
+			data: func() []byte {
+				data := buildTIFF([]testField{asciiField(tagMake, "Canon"), asciiField(tagModel, "EOS R5")}, nil, nil)
+				return data[:len(data)-3]
+			}(),
+			expected: &Metadata{CameraMake: "Canon"},
+		},
+		{
+			name:     "unknown field type",
+			data:     buildTIFF([]testField{{count: 1, data: []byte{1}, fieldType: 99, tag: tagOrientation}}, nil, nil),
+			expected: &Metadata{},
+		},
+		{
+			name:     "invalid orientation",
+			data:     buildTIFF([]testField{shortField(tagOrientation, 9)}, nil, nil),
+			expected: &Metadata{},
+		},
+		{
+			name: "invalid values",
+			data: buildTIFF(
+				nil,
+				[]testField{
+					asciiField(tagDateTimeOriginal, "0000:00:00 00:00:00"),
+					rationalField(tagFNumber, 4, 0),
+					rationalField(tagExposureTime, 0, 1),
+					shortField(tagISOSpeedRatings, 0),
+				},
+				[]testField{
+					asciiField(tagGPSLatitudeRef, "N"),
+					rationalField(tagGPSLatitude, 91, 1),
+					asciiField(tagGPSLongitudeRef, "E"),
+					rationalField(tagGPSLongitude, 13, 1),
+				},
+			),
+			expected: &Metadata{},
+		},
+		{
+			name: "GPS without longitude",
+			data: buildTIFF(
+				nil, nil,
+				[]testField{rationalField(tagGPSLatitude, 52, 1)},
+			),
+			expected: &Metadata{},
+		},
+		{
+			name: "time without offset",
+			data: buildTIFF(
+				[]testField{asciiField(tagDateTime, "2024:07:01 14:03:22")},
+				[]testField{asciiField(tagOffsetTimeOriginal, "+02:00")},
+				nil,
+			),
+			expected: &Metadata{TakenAt: "2024-07-01T14:03:22"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta := &Metadata{}
+			err := parseTIFF(bytes.NewReader(test.data), int64(len(test.data)), meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(meta, test.expected) {
+				t.Errorf("got %+v, expected %+v", meta, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseXMP(t *testing.T) {
+	fNumber := 2.8
+	exposureTime := 1.0 / 250
+	iso := 200
+	orientation := 3
+	altitude := -10.0
+
+	tests := []struct {
+		name     string
+		data     string
+		existing Metadata
+		expected Metadata
+	}{
+		{
+			name: "attributes",
+			data: `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description xmlns:tiff="http://ns.adobe.com/tiff/1.0/" xmlns:exif="http://ns.adobe.com/exif/1.0/"
+ tiff:Make="Canon" tiff:Model="EOS R5" tiff:Orientation="3" exif:FNumber="28/10" exif:ExposureTime="1/250"
+ exif:DateTimeOriginal="2024-07-01T14:03:22+02:00" exif:GPSLatitude="52,30.0N" exif:GPSLongitude="13,24,0W"
+ exif:GPSAltitude="10/1" exif:GPSAltitudeRef="1"/>
+</rdf:RDF></x:xmpmeta>`,
+			expected: Metadata{
+				CameraMake:   "Canon",
+				CameraModel:  "EOS R5",
+				ExposureTime: &exposureTime,
+				FNumber:      &fNumber,
+				GPS:          &GPSPosition{Altitude: &altitude, Latitude: 52.5, Longitude: -13.4},
+				Orientation:  &orientation,
+				TakenAt:      "2024-07-01T14:03:22+02:00",
+			},
+		},
+		{
+			name: "elements",
+			data: `<rdf:Description xmlns:exif="http://ns.adobe.com/exif/1.0/" xmlns:aux="http://ns.adobe.com/exif/1.0/aux/">
+<exif:ISOSpeedRatings><rdf:Seq><rdf:li>200</rdf:li></rdf:Seq></exif:ISOSpeedRatings>
+<aux:Lens>RF24-105mm F4 L IS USM</aux:Lens>
+<exif:DateTimeOriginal>2024-07-01T14:03</exif:DateTimeOriginal>
+</rdf:Description>`,
+			expected: Metadata{
+				ISO:       &iso,
+				LensModel: "RF24-105mm F4 L IS USM",
+				TakenAt:   "2024-07-01T14:03:00",
+			},
+		},
+		{
+			name:     "EXIF values are kept",
+			data:     `<rdf:Description tiff:Make="Nikon" tiff:Model="Z8"/>`,
+			existing: Metadata{CameraMake: "Canon"},
+			expected: Metadata{CameraMake: "Canon", CameraModel: "Z8"},
+		},
+		{
+			name:     "truncated",
+			data:     `<rdf:Description tiff:Make="Canon"><tiff:Model>EOS R5</tiff:Model><exif:FNumber>2`,
+			expected: Metadata{CameraMake: "Canon", CameraModel: "EOS R5"},
+		},
+		{
+			name:     "invalid values",
+			data:     `<rdf:Description tiff:Orientation="0" exif:FNumber="-2.8" exif:ExposureTime="1/0" exif:DateTimeOriginal="yesterday" exif:GPSLatitude="91,0N" exif:GPSLongitude="13,24E"/>`,
+			expected: Metadata{},
+		},
+		{
+			name:     "no XML",
+			data:     "\x00\xFF<<<",
+			expected: Metadata{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta := test.existing
+			parseXMP([]byte(test.data), &meta)
+
+			if !reflect.DeepEqual(normalizeMetadata(&meta), normalizeMetadata(&test.expected)) {
+				t.Errorf("got %+v, expected %+v", meta, test.expected)
+			}
+		})
+	}
+}
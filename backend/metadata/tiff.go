@@ -0,0 +1,363 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metadata
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// EXIF tags, which are read
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagExposureTime       = 0x829A
+	tagFNumber            = 0x829D
+	tagISOSpeedRatings    = 0x8827
+	tagISOSpeed           = 0x8833
+	tagDateTimeOriginal   = 0x9003
+	tagDateTimeDigitized  = 0x9004
+	tagOffsetTimeOriginal = 0x9011
+	tagFocalLength        = 0x920A
+	tagLensModel          = 0xA434
+
+	tagGPSLatitudeRef  = 0x0001
+	tagGPSLatitude     = 0x0002
+	tagGPSLongitudeRef = 0x0003
+	tagGPSLongitude    = 0x0004
+	tagGPSAltitudeRef  = 0x0005
+	tagGPSAltitude     = 0x0006
+)
+
+const (
+	// maxIFDEntries is the maximum number of entries of an IFD, which are read.
+	maxIFDEntries = 1000
+	// maxValueSize is the maximum size of a value, which is read.
+	maxValueSize = 64 * 1024
+)
+
+// typeSizes stores the size of a value for each TIFF field type.
+var typeSizes = map[uint16]int64{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+type tiffEntry struct {
+	count     int64
+	data      []byte
+	fieldType uint16
+	order     binary.ByteOrder
+}
+
+type tiffReader struct {
+	order   binary.ByteOrder
+	r       io.ReaderAt
+	size    int64
+	visited map[int64]bool
+}
+
+// parseTIFF reads the EXIF data of a TIFF structure into meta.
+// Broken IFDs are ignored, so that as much data as possible is read.
+func parseTIFF(r io.ReaderAt, size int64, meta *Metadata) error {
+	header := make([]byte, 8)
+	_, err := r.ReadAt(header, 0)
+	if err != nil {
+		return fmt.Errorf("invalid TIFF header: %w", err)
+	}
+
+	t := &tiffReader{
+		r:       r,
+		size:    size,
+		visited: map[int64]bool{},
+	}
+
+	switch string(header[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return fmt.Errorf("invalid TIFF byte order")
+	}
+	if t.order.Uint16(header[2:4]) != 42 {
+		return fmt.Errorf("invalid TIFF magic number")
+	}
+
+	ifd0 := t.readIFD(int64(t.order.Uint32(header[4:8])))
+
+	var exifIFD, gpsIFD map[uint16]*tiffEntry
+	if e := ifd0[tagExifIFD]; e != nil {
+		exifIFD = t.readIFD(int64(e.uint(0)))
+	}
+	if e := ifd0[tagGPSIFD]; e != nil {
+		gpsIFD = t.readIFD(int64(e.uint(0)))
+	}
+
+	meta.CameraMake = ifd0[tagMake].string()
+	meta.CameraModel = ifd0[tagModel].string()
+	if e := ifd0[tagOrientation]; e != nil {
+		orientation := int(e.uint(0))
+		if orientation >= 1 && orientation <= 8 {
+			meta.Orientation = &orientation
+		}
+	}
+
+	meta.TakenAt = formatExifTime(exifIFD[tagDateTimeOriginal].string(), exifIFD[tagOffsetTimeOriginal].string())
+	if meta.TakenAt == "" {
+		meta.TakenAt = formatExifTime(exifIFD[tagDateTimeDigitized].string(), "")
+	}
+	if meta.TakenAt == "" {
+		meta.TakenAt = formatExifTime(ifd0[tagDateTime].string(), "")
+	}
+
+	meta.ExposureTime = exifIFD[tagExposureTime].positiveRational()
+	meta.FNumber = exifIFD[tagFNumber].positiveRational()
+	meta.FocalLength = exifIFD[tagFocalLength].positiveRational()
+	meta.LensModel = exifIFD[tagLensModel].string()
+
+	for _, tag := range []uint16{tagISOSpeedRatings, tagISOSpeed} {
+		if e := exifIFD[tag]; e != nil && e.uint(0) > 0 {
+			iso := int(e.uint(0))
+			meta.ISO = &iso
+			break
+		}
+	}
+
+	if gpsIFD != nil {
+		meta.GPS = readGPS(gpsIFD)
+	}
+
+	return nil
+}
+
+// readIFD reads the entries of an IFD at a specific offset.
+// Returns nil, if the IFD is invalid.
+func (t *tiffReader) readIFD(offset int64) map[uint16]*tiffEntry {
+	if offset <= 0 || offset+2 > t.size || t.visited[offset] {
+		return nil
+	}
+	t.visited[offset] = true
+
+	buf := make([]byte, 2)
+	_, err := t.r.ReadAt(buf, offset)
+	if err != nil {
+		return nil
+	}
+
+	count := int64(t.order.Uint16(buf))
+	if count > maxIFDEntries {
+		return nil
+	}
+
+	entries := make([]byte, count*12)
+	_, err = t.r.ReadAt(entries, offset+2)
+	if err != nil {
+		return nil
+	}
+
+	ifd := map[uint16]*tiffEntry{}
+	for i := int64(0); i < count; i++ {
+		entry := entries[i*12 : (i+1)*12]
+
+		tag := t.order.Uint16(entry[0:2])
+		fieldType := t.order.Uint16(entry[2:4])
+		valueCount := int64(t.order.Uint32(entry[4:8]))
+
+		typeSize, ok := typeSizes[fieldType]
+		if !ok {
+			continue
+		}
+
+		valueSize := typeSize * valueCount
+		if valueSize > maxValueSize {
+			continue
+		}
+
+		var data []byte
+		if valueSize <= 4 {
+			data = entry[8 : 8+valueSize]
+		} else {
+			valueOffset := int64(t.order.Uint32(entry[8:12]))
+			if valueOffset+valueSize > t.size {
+				continue
+			}
+
+			data = make([]byte, valueSize)
+			_, err = t.r.ReadAt(data, valueOffset)
+			if err != nil {
+				continue
+			}
+		}
+
+		ifd[tag] = &tiffEntry{
+			count:     valueCount,
+			data:      data,
+			fieldType: fieldType,
+			order:     t.order,
+		}
+	}
+
+	return ifd
+}
+
+// positiveRational returns the first value as pointer,
+// if it is a valid number greater than 0.
+func (e *tiffEntry) positiveRational() *float64 {
+	value := e.rational(0)
+	if math.IsNaN(value) || math.IsInf(value, 0) || value <= 0 {
+		return nil
+	}
+
+	return &value
+}
+
+// rational returns the value at index i as floating point number
+// or NaN, if it is no rational value.
+func (e *tiffEntry) rational(i int64) float64 {
+	if e == nil || i >= e.count {
+		return math.NaN()
+	}
+
+	switch e.fieldType {
+	case 5:
+		numerator := e.order.Uint32(e.data[i*8:])
+		denominator := e.order.Uint32(e.data[i*8+4:])
+		if denominator == 0 {
+			return math.NaN()
+		}
+		return float64(numerator) / float64(denominator)
+	case 10:
+		numerator := int32(e.order.Uint32(e.data[i*8:]))
+		denominator := int32(e.order.Uint32(e.data[i*8+4:]))
+		if denominator == 0 {
+			return math.NaN()
+		}
+		return float64(numerator) / float64(denominator)
+	}
+
+	return float64(e.uint(i))
+}
+
+// string returns the value as trimmed text.
+func (e *tiffEntry) string() string {
+	if e == nil || (e.fieldType != 2 && e.fieldType != 7) {
+		return ""
+	}
+
+	text, _, _ := strings.Cut(string(e.data), "\x00")
+	return strings.TrimSpace(text)
+}
+
+// uint returns the integer value at index i or 0.
+func (e *tiffEntry) uint(i int64) uint32 {
+	if e == nil || i >= e.count {
+		return 0
+	}
+
+	switch e.fieldType {
+	case 1, 7:
+		return uint32(e.data[i])
+	case 3:
+		return uint32(e.order.Uint16(e.data[i*2:]))
+	case 4:
+		return e.order.Uint32(e.data[i*4:])
+	}
+
+	return 0
+}
+
+// formatExifTime converts an EXIF time like `2006:01:02 15:04:05` and an optional
+// offset like `+02:00` into the format of Metadata.TakenAt.
+// Returns an empty string, if the time is invalid.
+func formatExifTime(value string, offset string) string {
+	t, err := time.Parse("2006:01:02 15:04:05", strings.TrimSpace(value))
+	if err != nil || t.Year() < 1800 {
+		return ""
+	}
+
+	offset = strings.TrimSpace(offset)
+	if _, err := time.Parse("-07:00", offset); err != nil {
+		offset = ""
+	}
+
+	return t.Format("2006-01-02T15:04:05") + offset
+}
+
+func readGPS(ifd map[uint16]*tiffEntry) *GPSPosition {
+	latitude := readGPSCoordinate(ifd[tagGPSLatitude], ifd[tagGPSLatitudeRef].string(), "S")
+	longitude := readGPSCoordinate(ifd[tagGPSLongitude], ifd[tagGPSLongitudeRef].string(), "W")
+	if latitude == nil || longitude == nil || math.Abs(*latitude) > 90 || math.Abs(*longitude) > 180 {
+		return nil
+	}
+
+	position := &GPSPosition{
+		Latitude:  *latitude,
+		Longitude: *longitude,
+	}
+
+	altitude := ifd[tagGPSAltitude].rational(0)
+	if !math.IsNaN(altitude) && !math.IsInf(altitude, 0) {
+		if ifd[tagGPSAltitudeRef].uint(0) == 1 {
+			// below sea level
+			altitude = -altitude
+		}
+		position.Altitude = &altitude
+	}
+
+	return position
+}
+
+// readGPSCoordinate converts degrees, minutes and seconds to degrees,
+// which are negative, if ref is negativeRef.
+func readGPSCoordinate(e *tiffEntry, ref string, negativeRef string) *float64 {
+	if e == nil || e.count < 1 {
+		return nil
+	}
+
+	value := 0.0
+	for i, factor := range []float64{1, 60, 3600} {
+		if int64(i) >= e.count {
+			break
+		}
+
+		part := e.rational(int64(i))
+		if math.IsNaN(part) || math.IsInf(part, 0) {
+			return nil
+		}
+
+		value += part / factor
+	}
+
+	if strings.EqualFold(ref, negativeRef) {
+		value = -value
+	}
+
+	return &value
+}
@@ -0,0 +1,234 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metadata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xmpGPSCoordinatePattern matches XMP coordinates like `51,30.5N` or `51,30,15S`.
+var xmpGPSCoordinatePattern = regexp.MustCompile(`^(\d+),(\d+(?:\.\d+)?)(?:,(\d+(?:\.\d+)?))?([NSEW])$`)
+
+// xmpProperties stores the local names of the XMP properties, which are read.
+var xmpProperties = map[string]bool{
+	"CreateDate": true, "DateCreated": true, "DateTimeOriginal": true,
+	"ExposureTime": true, "FNumber": true, "FocalLength": true,
+	"GPSAltitude": true, "GPSAltitudeRef": true, "GPSLatitude": true, "GPSLongitude": true,
+	"ISOSpeedRatings": true, "Lens": true, "LensModel": true,
+	"Make": true, "Model": true, "Orientation": true, "PhotographicSensitivity": true,
+}
+
+// parseXMP reads the properties of an XMP packet, which can be written as
+// attributes or elements, and sets the fields of meta, which are still empty.
+func parseXMP(data []byte, meta *Metadata) {
+	values := map[string]string{}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	// the property and the text of each open element; the text is only
+	// used at the end of an element, so truncated values are ignored
+	type xmpElement struct {
+		property string
+		text     strings.Builder
+	}
+	stack := make([]*xmpElement, 0)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			for _, attr := range t.Attr {
+				if xmpProperties[attr.Name.Local] {
+					setXMPValue(values, attr.Name.Local, attr.Value)
+				}
+			}
+
+			current := &xmpElement{}
+			if xmpProperties[t.Name.Local] {
+				current.property = t.Name.Local
+			} else if len(stack) > 0 {
+				// like rdf:Seq and rdf:li
+				current.property = stack[len(stack)-1].property
+			}
+			stack = append(stack, current)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				current := stack[len(stack)-1]
+				if current.property != "" {
+					setXMPValue(values, current.property, current.text.String())
+				}
+
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if meta.TakenAt == "" {
+		for _, name := range []string{"DateTimeOriginal", "DateCreated", "CreateDate"} {
+			meta.TakenAt = formatXMPTime(values[name])
+			if meta.TakenAt != "" {
+				break
+			}
+		}
+	}
+	if meta.CameraMake == "" {
+		meta.CameraMake = values["Make"]
+	}
+	if meta.CameraModel == "" {
+		meta.CameraModel = values["Model"]
+	}
+	if meta.LensModel == "" {
+		meta.LensModel = values["LensModel"]
+	}
+	if meta.LensModel == "" {
+		meta.LensModel = values["Lens"]
+	}
+	if meta.Orientation == nil {
+		if orientation, err := strconv.Atoi(values["Orientation"]); err == nil && orientation >= 1 && orientation <= 8 {
+			meta.Orientation = &orientation
+		}
+	}
+	if meta.ISO == nil {
+		for _, name := range []string{"ISOSpeedRatings", "PhotographicSensitivity"} {
+			if iso, err := strconv.Atoi(values[name]); err == nil && iso > 0 {
+				meta.ISO = &iso
+				break
+			}
+		}
+	}
+	if meta.ExposureTime == nil {
+		meta.ExposureTime = parseXMPRational(values["ExposureTime"])
+	}
+	if meta.FNumber == nil {
+		meta.FNumber = parseXMPRational(values["FNumber"])
+	}
+	if meta.FocalLength == nil {
+		meta.FocalLength = parseXMPRational(values["FocalLength"])
+	}
+
+	if meta.GPS == nil {
+		latitude := parseXMPGPSCoordinate(values["GPSLatitude"])
+		longitude := parseXMPGPSCoordinate(values["GPSLongitude"])
+		if latitude != nil && longitude != nil && math.Abs(*latitude) <= 90 && math.Abs(*longitude) <= 180 {
+			meta.GPS = &GPSPosition{
+				Latitude:  *latitude,
+				Longitude: *longitude,
+			}
+
+			if altitude := parseXMPRational(values["GPSAltitude"]); altitude != nil {
+				if values["GPSAltitudeRef"] == "1" {
+					*altitude = -*altitude
+				}
+				meta.GPS.Altitude = altitude
+			}
+		}
+	}
+}
+
+// setXMPValue keeps the first non-empty value of a property.
+func setXMPValue(values map[string]string, name string, value string) {
+	value = strings.TrimSpace(value)
+	if value != "" && values[name] == "" {
+		values[name] = value
+	}
+}
+
+// formatXMPTime converts an XMP date like `2006-01-02T15:04:05+02:00`
+// into the format of Metadata.TakenAt.
+func formatXMPTime(value string) string {
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999Z07:00", "2006-01-02T15:04:05Z07:00", "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02T15:04:05Z07:00")
+		}
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", "2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02T15:04:05")
+		}
+	}
+
+	return ""
+}
+
+// parseXMPGPSCoordinate converts an XMP coordinate like `51,30.5N` to degrees.
+func parseXMPGPSCoordinate(value string) *float64 {
+	match := xmpGPSCoordinatePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil {
+		return nil
+	}
+
+	degrees, _ := strconv.ParseFloat(match[1], 64)
+	minutes, _ := strconv.ParseFloat(match[2], 64)
+	seconds := 0.0
+	if match[3] != "" {
+		seconds, _ = strconv.ParseFloat(match[3], 64)
+	}
+
+	coordinate := degrees + minutes/60 + seconds/3600
+	if match[4] == "S" || match[4] == "W" {
+		coordinate = -coordinate
+	}
+
+	return &coordinate
+}
+
+// parseXMPRational converts a value like `1/250` or `2.8`
+// to a number greater than 0.
+func parseXMPRational(value string) *float64 {
+	var result float64
+
+	if numerator, denominator, ok := strings.Cut(value, "/"); ok {
+		n, err1 := strconv.ParseFloat(numerator, 64)
+		d, err2 := strconv.ParseFloat(denominator, 64)
+		if err1 != nil || err2 != nil || d == 0 {
+			return nil
+		}
+		result = n / d
+	} else {
+		var err error
+		result, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil
+		}
+	}
+
+	if math.IsNaN(result) || math.IsInf(result, 0) || result <= 0 {
+		return nil
+	}
+
+	return &result
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+// FakeProvider is a deterministic vision provider without any model,
+// which is useful for tests and development.
+type FakeProvider struct {
+}
+
+// DescribeImage implements the method of types.VisionProvider interface.
+func (p *FakeProvider) DescribeImage(ctx context.Context, request *types.VisionRequest) (*types.ImageInformation, error) {
+	hash := sha256.Sum256(request.Data)
+	shortHash := hex.EncodeToString(hash[:])[:8]
+
+	tags := []string{"fake"}
+	if _, subType, ok := strings.Cut(request.MimeType, "/"); ok && subType != "" {
+		tags = append(tags, subType)
+	}
+
+	return &types.ImageInformation{
+		DetailedDescription: fmt.Sprintf(
+			"Fake description of '%s' (%s, %d bytes) for prompt: %s",
+			request.Filename, request.MimeType, len(request.Data), request.Prompt,
+		),
+		Tags:  tags,
+		Title: fmt.Sprintf("Image %s", shortHash),
+	}, nil
+}
+
+// Name implements the method of types.VisionProvider interface.
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// fakeEmbeddingDimensions is the length of the vectors of FakeEmbeddingProvider.
+const fakeEmbeddingDimensions = 64
+
+// FakeEmbeddingProvider is a deterministic embedding provider without
+// any model: texts with the same words get similar vectors.
+type FakeEmbeddingProvider struct {
+}
+
+// EmbedImage implements the method of types.EmbeddingProvider interface.
+func (p *FakeEmbeddingProvider) EmbedImage(ctx context.Context, data []byte, mimeType string) ([]float32, error) {
+	hash := sha256.Sum256(data)
+
+	vector := make([]float32, fakeEmbeddingDimensions)
+	for i := range vector {
+		vector[i] = float32(hash[i%len(hash)]) - 127.5
+	}
+	return vector, nil
+}
+
+// EmbedTexts implements the method of types.EmbeddingProvider interface.
+func (p *FakeEmbeddingProvider) EmbedTexts(ctx context.Context, texts []string) ([][]float32, error) {
+	vectorList := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector := make([]float32, fakeEmbeddingDimensions)
+
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		for _, word := range words {
+			h := fnv.New32a()
+			h.Write([]byte(word))
+			vector[h.Sum32()%fakeEmbeddingDimensions]++
+		}
+
+		vectorList = append(vectorList, vector)
+	}
+	return vectorList, nil
+}
+
+// Model implements the method of types.EmbeddingProvider interface.
+func (p *FakeEmbeddingProvider) Model() string {
+	return "fake"
+}
+
+// Name implements the method of types.EmbeddingProvider interface.
+func (p *FakeEmbeddingProvider) Name() string {
+	return "fake"
+}
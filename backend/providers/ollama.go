@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+// OllamaProvider is a vision provider using the generate API of an Ollama server.
+type OllamaProvider struct {
+	// Settings stores the underlying settings.
+	Settings *types.VisionSettings
+}
+
+// DescribeImage implements the method of types.VisionProvider interface.
+func (p *OllamaProvider) DescribeImage(ctx context.Context, request *types.VisionRequest) (*types.ImageInformation, error) {
+	baseUrl := strings.TrimSpace(p.Settings.Url)
+	if baseUrl == "" {
+		baseUrl = "http://host.docker.internal:11434"
+	}
+
+	model := strings.TrimSpace(p.Settings.Model)
+	if model == "" {
+		model = "llama3.2-vision"
+	}
+
+	images := make([]string, 0)
+	images = append(images, base64.StdEncoding.EncodeToString(request.Data))
+
+	body := map[string]any{
+		"model":  model,
+		"prompt": request.Prompt,
+		"stream": false,
+		"options": map[string]any{
+			"temperature": p.Settings.Temperature,
+		},
+		"images": images,
+		"format": types.ImageInformationSchema(),
+	}
+
+	var completionResponse types.OllamaApiCompletionResponse
+	err := postJSON(ctx, p.Settings.Timeout, strings.TrimSuffix(baseUrl, "/")+"/api/generate", &body, nil, &completionResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseImageInformation(completionResponse.Response)
+}
+
+// Name implements the method of types.VisionProvider interface.
+func (p *OllamaProvider) Name() string {
+	return "ollama"
+}
+
+// OllamaEmbeddingProvider is an embedding provider using the embed API of an Ollama server.
+type OllamaEmbeddingProvider struct {
+	// Settings stores the underlying settings.
+	Settings *types.EmbeddingSettings
+}
+
+// EmbedImage implements the method of types.EmbeddingProvider interface.
+func (p *OllamaEmbeddingProvider) EmbedImage(ctx context.Context, data []byte, mimeType string) ([]float32, error) {
+	return nil, fmt.Errorf("%w by ollama", types.ErrImageEmbeddingsNotSupported)
+}
+
+// EmbedTexts implements the method of types.EmbeddingProvider interface.
+func (p *OllamaEmbeddingProvider) EmbedTexts(ctx context.Context, texts []string) ([][]float32, error) {
+	baseUrl := strings.TrimSpace(p.Settings.Url)
+	if baseUrl == "" {
+		baseUrl = "http://host.docker.internal:11434"
+	}
+
+	body := map[string]any{
+		"model": p.Model(),
+		"input": texts,
+	}
+
+	var embedResponse types.OllamaApiEmbedResponse
+	err := postJSON(ctx, p.Settings.Timeout, strings.TrimSuffix(baseUrl, "/")+"/api/embed", &body, nil, &embedResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embedResponse.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embedResponse.Embeddings))
+	}
+
+	return embedResponse.Embeddings, nil
+}
+
+// Model implements the method of types.EmbeddingProvider interface.
+func (p *OllamaEmbeddingProvider) Model() string {
+	model := strings.TrimSpace(p.Settings.Model)
+	if model == "" {
+		model = "nomic-embed-text"
+	}
+	return model
+}
+
+// Name implements the method of types.EmbeddingProvider interface.
+func (p *OllamaEmbeddingProvider) Name() string {
+	return "ollama"
+}
@@ -0,0 +1,199 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+// OpenAIProvider is a vision provider using an OpenAI compatible
+// chat completion API, like llama.cpp, vLLM or LM Studio.
+type OpenAIProvider struct {
+	// Settings stores the underlying settings.
+	Settings *types.VisionSettings
+}
+
+// DescribeImage implements the method of types.VisionProvider interface.
+func (p *OpenAIProvider) DescribeImage(ctx context.Context, request *types.VisionRequest) (*types.ImageInformation, error) {
+	baseUrl := strings.TrimSpace(p.Settings.Url)
+	if baseUrl == "" {
+		baseUrl = "http://host.docker.internal:8000/v1"
+	}
+
+	dataUri := fmt.Sprintf(
+		"data:%s;base64,%s",
+		request.MimeType, base64.StdEncoding.EncodeToString(request.Data),
+	)
+
+	body := map[string]any{
+		"model": p.Settings.Model,
+		"messages": []map[string]any{
+			{
+				"role": "user",
+				"content": []map[string]any{
+					{
+						"type": "text",
+						"text": request.Prompt,
+					},
+					{
+						"type": "image_url",
+						"image_url": map[string]any{
+							"url": dataUri,
+						},
+					},
+				},
+			},
+		},
+		"temperature": p.Settings.Temperature,
+		"response_format": map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   "DescribeImageSchema",
+				"schema": types.ImageInformationSchema(),
+			},
+		},
+	}
+
+	headers := map[string]string{}
+	apiKey := strings.TrimSpace(p.Settings.ApiKey)
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+
+	var completionResponse types.OpenAIChatCompletionResponse
+	err := postJSON(ctx, p.Settings.Timeout, strings.TrimSuffix(baseUrl, "/")+"/chat/completions", &body, headers, &completionResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(completionResponse.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion returned no choices")
+	}
+
+	return parseImageInformation(completionResponse.Choices[0].Message.Content)
+}
+
+// Name implements the method of types.VisionProvider interface.
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// OpenAIEmbeddingProvider is an embedding provider using an OpenAI
+// compatible embeddings API.
+type OpenAIEmbeddingProvider struct {
+	// Settings stores the underlying settings.
+	Settings *types.EmbeddingSettings
+}
+
+// EmbedImage implements the method of types.EmbeddingProvider interface.
+// The image is sent as chat message, like vLLM does it for multimodal models.
+func (p *OpenAIEmbeddingProvider) EmbedImage(ctx context.Context, data []byte, mimeType string) ([]float32, error) {
+	dataUri := fmt.Sprintf(
+		"data:%s;base64,%s",
+		mimeType, base64.StdEncoding.EncodeToString(data),
+	)
+
+	body := map[string]any{
+		"model": p.Model(),
+		"messages": []map[string]any{
+			{
+				"role": "user",
+				"content": []map[string]any{
+					{
+						"type": "image_url",
+						"image_url": map[string]any{
+							"url": dataUri,
+						},
+					},
+				},
+			},
+		},
+		"encoding_format": "float",
+	}
+
+	vectorList, err := p.postEmbeddings(ctx, body, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return vectorList[0], nil
+}
+
+// EmbedTexts implements the method of types.EmbeddingProvider interface.
+func (p *OpenAIEmbeddingProvider) EmbedTexts(ctx context.Context, texts []string) ([][]float32, error) {
+	body := map[string]any{
+		"model":           p.Model(),
+		"input":           texts,
+		"encoding_format": "float",
+	}
+
+	return p.postEmbeddings(ctx, body, len(texts))
+}
+
+// Model implements the method of types.EmbeddingProvider interface.
+func (p *OpenAIEmbeddingProvider) Model() string {
+	return strings.TrimSpace(p.Settings.Model)
+}
+
+// Name implements the method of types.EmbeddingProvider interface.
+func (p *OpenAIEmbeddingProvider) Name() string {
+	return "openai"
+}
+
+func (p *OpenAIEmbeddingProvider) postEmbeddings(ctx context.Context, body map[string]any, count int) ([][]float32, error) {
+	baseUrl := strings.TrimSpace(p.Settings.Url)
+	if baseUrl == "" {
+		baseUrl = "http://host.docker.internal:8000/v1"
+	}
+
+	headers := map[string]string{}
+	apiKey := strings.TrimSpace(p.Settings.ApiKey)
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+
+	var embeddingsResponse types.OpenAIEmbeddingsResponse
+	err := postJSON(ctx, p.Settings.Timeout, strings.TrimSuffix(baseUrl, "/")+"/embeddings", &body, headers, &embeddingsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embeddingsResponse.Data) != count {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", count, len(embeddingsResponse.Data))
+	}
+
+	vectorList := make([][]float32, count)
+	for _, item := range embeddingsResponse.Data {
+		if item.Index < 0 || item.Index >= count {
+			return nil, fmt.Errorf("invalid embedding index %d", item.Index)
+		}
+		vectorList[item.Index] = item.Embedding
+	}
+
+	return vectorList, nil
+}
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+// answerWithImageInformation is the structure of the JSON
+// answer of a vision model.
+type answerWithImageInformation struct {
+	ImageInformation *types.ImageInformation `json:"image_information"`
+}
+
+// NewEmbeddingProvider creates a new embedding provider based on settings.
+func NewEmbeddingProvider(settings *types.EmbeddingSettings) (types.EmbeddingProvider, error) {
+	providerName := strings.TrimSpace(strings.ToLower(settings.Provider))
+
+	switch providerName {
+	case "", "ollama":
+		return &OllamaEmbeddingProvider{
+			Settings: settings,
+		}, nil
+	case "openai":
+		return &OpenAIEmbeddingProvider{
+			Settings: settings,
+		}, nil
+	case "fake":
+		return &FakeEmbeddingProvider{}, nil
+	}
+
+	return nil, fmt.Errorf("embedding provider '%s' is not supported", settings.Provider)
+}
+
+// NewVisionProvider creates a new vision provider based on settings.
+func NewVisionProvider(settings *types.VisionSettings) (types.VisionProvider, error) {
+	providerName := strings.TrimSpace(strings.ToLower(settings.Provider))
+
+	switch providerName {
+	case "", "ollama":
+		return &OllamaProvider{
+			Settings: settings,
+		}, nil
+	case "openai":
+		return &OpenAIProvider{
+			Settings: settings,
+		}, nil
+	case "fake":
+		return &FakeProvider{}, nil
+	}
+
+	return nil, fmt.Errorf("vision provider '%s' is not supported", settings.Provider)
+}
+
+func parseImageInformation(answer string) (*types.ImageInformation, error) {
+	var parsedAnswer answerWithImageInformation
+	err := json.Unmarshal([]byte(answer), &parsedAnswer)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsedAnswer.ImageInformation == nil {
+		return nil, fmt.Errorf("answer contains no image information")
+	}
+
+	return parsedAnswer.ImageInformation, nil
+}
+
+// httpClients stores the shared HTTP clients by their timeout.
+var httpClients sync.Map
+
+// getHTTPClient returns the shared HTTP client with a timeout, 0 for none.
+func getHTTPClient(timeout time.Duration) *http.Client {
+	client, _ := httpClients.LoadOrStore(timeout, &http.Client{Timeout: timeout})
+
+	return client.(*http.Client)
+}
+
+func postJSON(ctx context.Context, timeout time.Duration, url string, body any, headers map[string]string, result any) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+
+	// setup ...
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	// ... and finally send the JSON data
+	resp, err := getHTTPClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseData, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		if err == nil {
+			return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseData))
+		}
+		return fmt.Errorf("request failed with status %d and error reading response body", resp.StatusCode)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(responseData, result)
+}
@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+type getDuplicatesResponse struct {
+	Groups    []types.DuplicateGroup `json:"groups"`
+	Threshold int                    `json:"threshold"`
+}
+
+type setPreferredImageRequest struct {
+	Preferred bool `json:"preferred"`
+}
+
+type setPreferredImageResponse struct {
+	Name      string `json:"name"`
+	Preferred bool   `json:"preferred"`
+}
+
+// CreateGetDuplicatesHandler creates handler for `/api/duplicates` route.
+func CreateGetDuplicatesHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		threshold, err := getIntQueryParam(r.URL.Query().Get("threshold"), 10, 0, 32)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		groups, err := app.FindDuplicates(db, threshold)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		response := getDuplicatesResponse{
+			Groups:    groups,
+			Threshold: threshold,
+		}
+
+		sendJSON(app, w, 200, response)
+	}
+}
+
+// CreateSetPreferredImageHandler creates handler for `/api/images/{imagename}/preferred` route.
+func CreateSetPreferredImageHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		imageName := vars["imagename"]
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		var request setPreferredImageRequest
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		err = app.SetPreferredImage(db, imageName, request.Preferred)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
+
+		imageName, _ = types.CleanImageName(imageName)
+		response := setPreferredImageResponse{
+			Name:      imageName,
+			Preferred: request.Preferred,
+		}
+
+		sendJSON(app, w, 200, response)
+	}
+}
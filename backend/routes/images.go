@@ -23,95 +23,166 @@
 package routes
 
 import (
-	"bytes"
-	"encoding/base64"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"slices"
-	"sort"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/mkloubert/my-ai-gallery/types"
 )
 
 type getImageResponse struct {
-	Images []getImageResponseImage `json:"images"`
+	Images     []getImageResponseImage `json:"images"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	Total      int                     `json:"total"`
 }
 
 type getImageResponseImage struct {
-	Info *getImageResponseImageInfo `json:"info"`
-	Name string                     `json:"name"`
-	Url  string                     `json:"url"`
+	Exif         *types.ImageMetadata       `json:"exif,omitempty"`
+	Filesize     int64                      `json:"filesize"`
+	Height       *int                       `json:"height,omitempty"`
+	Info         *getImageResponseImageInfo `json:"info"`
+	MimeType     string                     `json:"mime_type"`
+	Modified     string                     `json:"modified"`
+	Name         string                     `json:"name"`
+	Status       types.ImageStatus          `json:"status"`
+	ThumbnailUrl string                     `json:"thumbnail_url"`
+	Url          string                     `json:"url"`
+	Width        *int                       `json:"width,omitempty"`
 }
 
 type getImageResponseImageInfo struct {
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
-	Title       string   `json:"title"`
+	Description string                 `json:"description"`
+	Locks       types.ImageMetaLocks   `json:"locks"`
+	Sources     types.ImageMetaSources `json:"sources"`
+	Tags        []string               `json:"tags"`
+	Title       string                 `json:"title"`
 }
 
-type imageDescriptionResponse struct {
-	FileModifiationTime string                                   `json:"file_modifiation_time,omitempty"`
-	Filename            string                                   `json:"filename,omitempty"`
-	Filesize            int64                                    `json:"filesize,omitempty"`
-	ImageInformation    imageDescriptionResponseImageInformation `json:"image_information,omitempty"`
+type moveImageRequest struct {
+	Target string `json:"target"`
+}
+
+type moveImageResponse struct {
+	Name         string `json:"name"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	Url          string `json:"url"`
 }
 
-type imageDescriptionResponseImageInformation struct {
-	DetailedDescription string   `json:"detailed_description"`
-	Tags                []string `json:"tags"`
-	Title               string   `json:"title"`
+type similarImagesResponse struct {
+	By     string                       `json:"by"`
+	Images []similarImagesResponseImage `json:"images"`
+	Name   string                       `json:"name"`
+}
+
+type similarImagesResponseImage struct {
+	types.SimilarImage
+	ThumbnailUrl string `json:"thumbnail_url"`
+	Url          string `json:"url"`
+}
+
+type imageDescriptionResponse struct {
+	FileModifiationTime string                 `json:"file_modifiation_time,omitempty"`
+	Filename            string                 `json:"filename,omitempty"`
+	Filesize            int64                  `json:"filesize,omitempty"`
+	ImageInformation    types.ImageInformation `json:"image_information,omitempty"`
 }
 
 // CreateHandleGetImageHandler creates handler for `/api/images/{imagename}` route.
 func CreateGetImageHandler(app *types.AppContext) types.HttpHandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		imageFolder := app.GetImageFolder()
-
 		vars := mux.Vars(r)
 		imageName := vars["imagename"]
 
-		fullPath := filepath.Join(imageFolder, imageName)
+		_, fullPath, err := app.ResolveImagePath(imageName)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
 
 		file, err := os.Open(fullPath)
 		if err != nil {
-			app.SendHttpError(err, w)
+			app.SendImageError(err, w)
 			return
 		}
 		defer file.Close()
 
+		info, err := file.Stat()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		if info.IsDir() {
+			app.SendImageError(fmt.Errorf("%w: '%s' is a folder", types.ErrImageNotFound, imageName), w)
+			return
+		}
+
 		buf := make([]byte, 512)
-		_, err = file.Read(buf)
-		if err != nil && err.Error() != "EOF" {
+		n, err := io.ReadFull(file, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
 			app.SendHttpError(err, w)
 			return
 		}
 
-		mimeType := http.DetectContentType(buf)
+		mimeType := http.DetectContentType(buf[:n])
+
+		serveFile(app, w, r, file, info, mimeType)
+	}
+}
+
+// CreateGetImageThumbnailHandler creates handler for `/api/images/{imagename}/thumb` route.
+func CreateGetImageThumbnailHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		imageName := vars["imagename"]
+
+		size := app.GetDefaultThumbnailSize()
+		if value := strings.TrimSpace(r.URL.Query().Get("size")); value != "" {
+			var err error
+			size, err = strconv.Atoi(value)
+			if err != nil || !slices.Contains(app.Config.Thumbnails.Sizes, size) {
+				app.SendHttpErrorWithStatus(400, fmt.Errorf("thumbnail size '%s' is not supported", value), w)
+				return
+			}
+		}
+
+		thumbnailFile, err := app.GetThumbnail(imageName, size)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
 
-		file.Seek(0, 0)
+		file, err := os.Open(thumbnailFile)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer file.Close()
 
-		w.Header().Set("Content-Type", mimeType)
+		info, err := file.Stat()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
 
-		io.Copy(w, file)
+		serveFile(app, w, r, file, info, "image/jpeg")
 	}
 }
 
 // CreateHandleGetImagesHandler creates handler for `/api/images` route.
 func CreateGetImagesHandler(app *types.AppContext) types.HttpHandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		imageFolder := app.GetImageFolder()
-
-		files, err := os.ReadDir(imageFolder)
+		query, err := parseImageListQuery(r.URL.Query())
 		if err != nil {
-			app.SendHttpError(err, w)
+			app.SendHttpErrorWithStatus(400, err, w)
 			return
 		}
 
@@ -122,134 +193,190 @@ func CreateGetImagesHandler(app *types.AppContext) types.HttpHandlerFunc {
 		}
 		defer db.Close()
 
-		newResponse := getImageResponse{}
-		newResponse.Images = make([]getImageResponseImage, 0)
+		list, err := app.ListImages(db, query)
+		if errors.Is(err, types.ErrInvalidImageListQuery) {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
 
-		for _, f := range files {
-			filePath := f.Name()
-			row := db.QueryRow("SELECT title, description, tags FROM images WHERE file_path = ?;", filePath)
+		newResponse := getImageResponse{
+			Images:     make([]getImageResponseImage, 0, len(list.Images)),
+			NextCursor: list.NextCursor,
+			Total:      list.Total,
+		}
 
-			found := true
+		for _, image := range list.Images {
+			newImage := getImageResponseImage{
+				Exif:         image.Exif,
+				Filesize:     image.Filesize,
+				Height:       image.Height,
+				MimeType:     image.MimeType,
+				Modified:     image.Modified,
+				Name:         image.Name,
+				Status:       image.Status,
+				ThumbnailUrl: app.GetThumbnailUrl(image.Name),
+				Url:          types.ImageUrl(image.Name),
+				Width:        image.Width,
+			}
 
-			var title, description, tags string
-			err = row.Scan(&title, &description, &tags)
-			if err != nil {
-				found = false
+			if image.Info != nil {
+				newImage.Info = &getImageResponseImageInfo{
+					Description: image.Info.Description,
+					Locks:       image.Info.Locks,
+					Sources:     image.Info.Sources,
+					Tags:        image.Info.Tags,
+					Title:       image.Info.Title,
+				}
 			}
 
-			func() {
-				fullPath := filepath.Join(imageFolder, f.Name())
+			newResponse.Images = append(newResponse.Images, newImage)
+		}
 
-				file, err := os.Open(fullPath)
-				if err != nil {
-					return
-				}
-				defer file.Close()
+		jsonData, err := json.Marshal(&newResponse)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
 
-				buf := make([]byte, 512)
-				_, err = file.Read(buf)
-				if err != nil && err.Error() != "EOF" {
-					return
-				}
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.Write(jsonData)
+	}
+}
 
-				mimeType := http.DetectContentType(buf)
-				if !strings.HasPrefix(mimeType, "image/") {
-					return
-				}
+// CreateGetSimilarImagesHandler creates handler for `/api/images/{imagename}/similar` route.
+func CreateGetSimilarImagesHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		imageName := vars["imagename"]
 
-				newImage := getImageResponseImage{}
-				newImage.Name = f.Name()
-				newImage.Url = fmt.Sprintf("/api/images/%s", url.PathEscape(f.Name()))
+		by := strings.TrimSpace(strings.ToLower(r.URL.Query().Get("by")))
+		if by != "" && by != types.SimilarByEmbedding && by != types.SimilarByHash {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("'by' must be '%s' or '%s'", types.SimilarByEmbedding, types.SimilarByHash), w)
+			return
+		}
 
-				if found {
-					tagList := make([]string, 0)
+		limit, err := getIntQueryParam(r.URL.Query().Get("limit"), 20, 1, 200)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid limit: %w", err), w)
+			return
+		}
 
-					parts := strings.Split(tags, ",")
-					for _, p := range parts {
-						p = strings.TrimSpace(strings.ToLower(p))
-						if p == "" {
-							continue
-						}
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
 
-						if !slices.Contains(tagList, p) {
-							tagList = append(tagList, p)
-						}
-					}
+		result, err := app.FindSimilarImages(db, imageName, by, limit)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
 
-					sort.Strings(tagList)
+		newResponse := similarImagesResponse{
+			By:     result.By,
+			Images: make([]similarImagesResponseImage, 0, len(result.Images)),
+			Name:   result.Name,
+		}
+		for _, image := range result.Images {
+			newResponse.Images = append(newResponse.Images, similarImagesResponseImage{
+				SimilarImage: image,
+				ThumbnailUrl: app.GetThumbnailUrl(image.Name),
+				Url:          types.ImageUrl(image.Name),
+			})
+		}
 
-					newInfo := &getImageResponseImageInfo{
-						Title:       strings.TrimSpace(title),
-						Description: strings.TrimSpace(description),
-						Tags:        tagList,
-					}
+		sendJSON(app, w, 200, &newResponse)
+	}
+}
 
-					newImage.Info = newInfo
-				}
+// CreateDeleteImageHandler creates handler for DELETE `/api/images/{imagename}` route.
+func CreateDeleteImageHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		imageName := vars["imagename"]
 
-				newResponse.Images = append(newResponse.Images, newImage)
-			}()
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
 		}
+		defer db.Close()
 
-		jsonData, err := json.Marshal(&newResponse)
+		err = app.DeleteImage(db, imageName)
 		if err != nil {
-			app.SendHttpError(err, w)
+			app.SendImageError(err, w)
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-		w.Write(jsonData)
+		w.WriteHeader(204)
 	}
 }
 
-// CreateUpdateImageMetaHandler creates handler for `/api/images/{imagename}/meta` route.
-func CreateUpdateImageMetaHandler(app *types.AppContext) types.HttpHandlerFunc {
+// CreateMoveImageHandler creates handler for `/api/images/{imagename}/move` route.
+func CreateMoveImageHandler(app *types.AppContext) types.HttpHandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		imageFolder := app.GetImageFolder()
-
 		vars := mux.Vars(r)
 		imageName := vars["imagename"]
 
-		fullPath := filepath.Join(imageFolder, imageName)
-
-		fmt.Printf("Patching meta of file '%s' ...%s", fullPath, fmt.Sprintln())
-
-		info, err := os.Stat(fullPath)
+		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			app.SendHttpError(err, w)
 			return
 		}
 
-		// get file size and last update time
-		filesize := info.Size()
-		fileModTime := info.ModTime().UTC().Format(time.RFC3339)
-
-		fmt.Printf("Size of file '%s': %d%s", fullPath, filesize, fmt.Sprintln())
-		fmt.Printf("Change date of file '%s': %s%s", fullPath, fileModTime, fmt.Sprintln())
+		var request moveImageRequest
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
 
-		file, err := os.Open(fullPath)
+		db, err := app.OpenImageDatabase()
 		if err != nil {
 			app.SendHttpError(err, w)
 			return
 		}
-		defer file.Close()
+		defer db.Close()
 
-		imageData, err := io.ReadAll(file)
+		newName, err := app.MoveImage(db, imageName, request.Target)
 		if err != nil {
-			app.SendHttpError(err, w)
+			app.SendImageError(err, w)
 			return
 		}
 
-		mimeType := http.DetectContentType(imageData)
-		base64ImageData := base64.StdEncoding.EncodeToString(imageData)
+		sendJSON(app, w, 200, &moveImageResponse{
+			Name:         newName,
+			ThumbnailUrl: app.GetThumbnailUrl(newName),
+			Url:          types.ImageUrl(newName),
+		})
+	}
+}
 
-		fmt.Printf("Mime of file '%s': %s%s", fullPath, mimeType, fmt.Sprintln())
+// CreateSetImageMetaHandler creates handler for PUT `/api/images/{imagename}/meta` route,
+// which saves metadata written by a user.
+func CreateSetImageMetaHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		imageName := vars["imagename"]
 
-		defer func() {
-			imageData = nil
-		}()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
 
-		fmt.Printf("Opening database for file '%s' ...%s", fullPath, fmt.Sprintln())
+		var input types.ImageMetaInput
+		err = json.Unmarshal(body, &input)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
 
 		db, err := app.OpenImageDatabase()
 		if err != nil {
@@ -258,186 +385,205 @@ func CreateUpdateImageMetaHandler(app *types.AppContext) types.HttpHandlerFunc {
 		}
 		defer db.Close()
 
-		// lang := "english"
-
-		/*
-					systemPrompt := fmt.Sprintf(`You are an AI assistant that helps users organize their photo collections.
-			For each provided image file, generate:
-			- A concise and informative description of the image in natural '%s' language, suitable for someone who cannot see the photo.
-			- A short and descriptive title of the main objects in the image.
-			- A set of relevant tags that summarize the main objects, themes, activities, and visual elements present in the image. The tags should be lowercase, and without special characters.
-			Be objective and accurate. Do not include personal opinions or assumptions that cannot be verified from the image itself.`, lang)
-		*/
-
-		responseSchema := &map[string]any{
-			"type":     "object",
-			"required": []string{"image_information", "tags", "title"},
-			"properties": map[string]any{
-				"image_information": map[string]any{
-					"type":        "object",
-					"description": "Information about the image.",
-					"required":    []string{"detailed_description", "tags", "title"},
-					"properties": map[string]any{
-						"detailed_description": map[string]any{
-							"description": "A detailed description what is in the image.",
-							"type":        "string",
-						},
-						"tags": map[string]any{
-							"type":    "array",
-							"minimum": 1,
-							"maximum": 10,
-							"items": map[string]any{
-								"type":        "string",
-								"description": "Word or small text that categorized the image.",
-							},
-						},
-						"title": map[string]any{
-							"description": "A short and descriptive title for the image.",
-							"type":        "string",
-						},
-					},
-				},
-			},
-		}
-
-		// responseSchemaName := "DescribeImageSchema"
-
-		url := "http://host.docker.internal:11434/api/generate"
-		prompt := "What is in this image?"
-		temperature := 0.3
-
-		model := strings.TrimSpace(os.Getenv("MAIG_IMAGE_MODEL"))
-		if model == "" {
-			model = "llama3.2-vision"
-		}
-
-		fmt.Printf("AI setting TEMPERATURE for file '%s': %f%s", fullPath, temperature, fmt.Sprintln())
-		fmt.Printf("AI setting MODEL for file '%s': %s%s", fullPath, model, fmt.Sprintln())
-
-		images := make([]string, 0)
-		images = append(images, base64ImageData)
-
-		body := map[string]any{
-			"model":       model,
-			"prompt":      prompt,
-			"stream":      false,
-			"temperature": temperature,
-			"images":      images,
-			"format":      responseSchema,
-		}
-
-		jsonData, err := json.Marshal(&body)
+		meta, err := app.SetImageMeta(r.Context(), db, imageName, &input)
 		if err != nil {
-			app.SendHttpError(err, w)
+			app.SendImageError(err, w)
 			return
 		}
 
-		fmt.Printf("POST to '%s' for file '%s' ...%s", url, fullPath, fmt.Sprintln())
+		sendJSON(app, w, 200, meta)
+	}
+}
 
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+// CreateSetImageMetaLocksHandler creates handler for PUT `/api/images/{imagename}/locks` route.
+func CreateSetImageMetaLocksHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		imageName := vars["imagename"]
+
+		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			app.SendHttpError(err, w)
 			return
 		}
 
-		// setup ...
-		req.Header.Set("Content-Type", "application/json")
-		// ... and finally send the JSON data
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		var input types.ImageMetaLocksInput
+		err = json.Unmarshal(body, &input)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+
+		db, err := app.OpenImageDatabase()
 		if err != nil {
 			app.SendHttpError(err, w)
 			return
 		}
-		defer resp.Body.Close()
+		defer db.Close()
 
-		if resp.StatusCode != 200 {
-			var errToSend error
+		meta, err := app.SetImageMetaLocks(db, imageName, &input)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
 
-			responseData, err := io.ReadAll(resp.Body)
-			if err == nil {
-				errToSend = fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseData))
-			} else {
-				errToSend = fmt.Errorf("request failed with status %d and error reading response body", resp.StatusCode)
-			}
+		sendJSON(app, w, 200, meta)
+	}
+}
+
+// CreateUpdateImageMetaHandler creates handler for `/api/images/{imagename}/meta` route.
+func CreateUpdateImageMetaHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		imageName := vars["imagename"]
 
-			app.SendHttpError(errToSend, w)
+		tagMode, err := types.ParseTagMode(r.URL.Query().Get("tag_mode"), "")
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
 			return
 		}
 
-		fmt.Printf("Loading response from '%s' for file '%s' ...%s", url, fullPath, fmt.Sprintln())
-
-		// load the response
-		responseData, err := io.ReadAll(resp.Body)
+		db, err := app.OpenImageDatabase()
 		if err != nil {
 			app.SendHttpError(err, w)
 			return
 		}
+		defer db.Close()
 
-		fmt.Printf("Marshalling response from '%s' for file '%s' ...%s", url, fullPath, fmt.Sprintln())
+		update, err := app.UpdateImageMeta(r.Context(), db, imageName, tagMode)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
+
+		imageDescription := imageDescriptionResponse{
+			FileModifiationTime: update.FileModificationTime,
+			Filename:            update.Filename,
+			Filesize:            update.Filesize,
+			ImageInformation:    *update.ImageInformation,
+		}
 
-		var completionResponse types.OllamaApiCompletionResponse
-		err = json.Unmarshal(responseData, &completionResponse)
+		cleanJsonData, err := json.Marshal(&imageDescription)
 		if err != nil {
 			app.SendHttpError(err, w)
 			return
 		}
 
-		answer := completionResponse.Response
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write(cleanJsonData)
+	}
+}
+
+// CreateRetagImagesHandler creates handler for `/api/images/retag` route,
+// which starts a tagging job for all images with a specific status.
+func CreateRetagImagesHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scope := strings.TrimSpace(strings.ToLower(r.URL.Query().Get("status")))
+		if scope == "" {
+			scope = types.JobScopeStale
+		}
 
-		fmt.Printf("Marshalling final response from '%s' for file '%s' ...%s", url, fullPath, fmt.Sprintln())
+		if scope != types.JobScopeStale && scope != types.JobScopeUntagged {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("status '%s' cannot be re-tagged", scope), w)
+			return
+		}
 
-		// ensure we have correct response ...
-		var imageDescription imageDescriptionResponse
-		err = json.Unmarshal([]byte(answer), &imageDescription)
+		job, err := app.Jobs.CreateJob("tag", scope, nil)
 		if err != nil {
 			app.SendHttpError(err, w)
 			return
 		}
 
-		// update in database
-		{
-			fmt.Printf("Updating new data for file '%s' ...%s%s", url, fullPath, fmt.Sprintln())
+		sendJSON(app, w, 202, job)
+	}
+}
 
-			stmt, err := db.Prepare(`INSERT INTO images
-(file_path, title, description, tags, last_filesize, last_modified) VALUES (?, ?, ?, ?, ?, ?)
-ON CONFLICT(file_path) DO UPDATE SET
-    description=excluded.description,
-    tags=excluded.tags,
-	title=excluded.title,
-    last_filesize=excluded.last_filesize,
-    last_modified=excluded.last_modified,
-	updated_at=CURRENT_TIMESTAMP;`)
-			if err != nil {
-				app.SendHttpError(err, w)
-				return
-			}
+func newImageMetaSources(title sql.NullString, description sql.NullString, tags sql.NullString) types.ImageMetaSources {
+	return types.ImageMetaSources{
+		Description: types.MetaSource(description.String),
+		Tags:        types.MetaSource(tags.String),
+		Title:       types.MetaSource(title.String),
+	}
+}
 
-			defer stmt.Close()
-
-			_, err = stmt.Exec(
-				imageDescription.Filename,
-				imageDescription.ImageInformation.Title,
-				imageDescription.ImageInformation.DetailedDescription,
-				strings.Join(imageDescription.ImageInformation.Tags, ","),
-				filesize,
-				fileModTime,
-			)
-			if err != nil {
-				app.SendHttpError(err, w)
-				return
-			}
+func parseImageListQuery(values url.Values) (*types.ImageListQuery, error) {
+	query := &types.ImageListQuery{
+		Cursor:    strings.TrimSpace(values.Get("cursor")),
+		Folder:    values.Get("folder"),
+		Recursive: strings.TrimSpace(strings.ToLower(values.Get("recursive"))) != "false",
+		Sort:      strings.TrimSpace(strings.ToLower(values.Get("sort"))),
+		Tags:      values["tag"],
+	}
+
+	var err error
+	query.Statuses, err = parseImageStatusFilter(values.Get("status"))
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.TrimSpace(strings.ToLower(values.Get("order"))) {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return nil, fmt.Errorf("order '%s' is not supported", values.Get("order"))
+	}
+
+	query.Limit, err = getIntQueryParam(values.Get("limit"), 0, 0, 10000)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit: %w", err)
+	}
+
+	if value := strings.TrimSpace(values.Get("has_metadata")); value != "" {
+		hasMetadata, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid has_metadata: %w", err)
 		}
+		query.HasMetadata = &hasMetadata
+	}
 
-		fmt.Printf("Preparing response for file '%s' ...%s", fullPath, fmt.Sprintln())
+	for _, p := range strings.Split(values.Get("mime"), ",") {
+		mimeType := strings.TrimSpace(strings.ToLower(p))
+		if mimeType != "" {
+			query.MimeTypes = append(query.MimeTypes, mimeType)
+		}
+	}
 
-		cleanJsonData, err := json.Marshal(&imageDescription)
+	for _, date := range []struct {
+		name   string
+		target *string
+	}{
+		{"taken_from", &query.TakenFrom},
+		{"taken_to", &query.TakenTo},
+		{"modified_from", &query.ModifiedFrom},
+		{"modified_to", &query.ModifiedTo},
+	} {
+		*date.target, err = types.ParseImageListDate(values.Get(date.name))
 		if err != nil {
-			app.SendHttpError(err, w)
-			return
+			return nil, err
 		}
+	}
 
-		w.WriteHeader(200)
-		w.Write(cleanJsonData)
+	return query, nil
+}
+
+func parseImageStatusFilter(value string) ([]types.ImageStatus, error) {
+	statusList := make([]types.ImageStatus, 0)
+
+	for _, p := range strings.Split(value, ",") {
+		status := types.ImageStatus(strings.TrimSpace(strings.ToLower(p)))
+		if status == "" {
+			continue
+		}
+
+		switch status {
+		case types.ImageStatusFresh, types.ImageStatusStale, types.ImageStatusUntagged:
+			statusList = append(statusList, status)
+		default:
+			return nil, fmt.Errorf("status '%s' is not supported", status)
+		}
 	}
+
+	return statusList, nil
 }
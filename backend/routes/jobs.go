@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+type getJobsResponse struct {
+	Jobs []types.Job `json:"jobs"`
+}
+
+type startJobRequest struct {
+	Action string   `json:"action"`
+	Names  []string `json:"names"`
+	Scope  string   `json:"scope"`
+}
+
+// CreateGetJobHandler creates handler for `/api/jobs/{id}` route.
+func CreateGetJobHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		jobId, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid job ID '%s'", vars["id"]), w)
+			return
+		}
+
+		job, err := app.Jobs.GetJob(jobId, true)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		if job == nil {
+			app.SendHttpErrorWithStatus(404, fmt.Errorf("job %d not found", jobId), w)
+			return
+		}
+
+		sendJSON(app, w, 200, job)
+	}
+}
+
+// CreateGetJobsHandler creates handler for GET `/api/jobs` route.
+func CreateGetJobsHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobs, err := app.Jobs.GetJobs(100)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		sendJSON(app, w, 200, &getJobsResponse{
+			Jobs: jobs,
+		})
+	}
+}
+
+// CreateStartJobHandler creates handler for POST `/api/jobs` route.
+func CreateStartJobHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		var request startJobRequest
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+
+		job, err := app.Jobs.CreateJob(request.Action, request.Scope, request.Names)
+		if errors.Is(err, types.ErrInvalidJob) || errors.Is(err, types.ErrInvalidImagePath) {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		sendJSON(app, w, 202, job)
+	}
+}
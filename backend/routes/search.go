@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+type searchResponse struct {
+	Images []searchResponseImage `json:"images"`
+	Total  int                   `json:"total"`
+}
+
+type searchResponseImage struct {
+	types.SearchResultImage
+	ThumbnailUrl string `json:"thumbnail_url"`
+	Url          string `json:"url"`
+}
+
+type semanticSearchResponse struct {
+	Images []semanticSearchResponseImage `json:"images"`
+}
+
+type semanticSearchResponseImage struct {
+	types.SemanticSearchResultImage
+	ThumbnailUrl string `json:"thumbnail_url"`
+	Url          string `json:"url"`
+}
+
+// CreateSearchHandler creates handler for `/api/search` route.
+func CreateSearchHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := getIntQueryParam(query.Get("limit"), 50, 1, 1000)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid limit: %w", err), w)
+			return
+		}
+
+		offset, err := getIntQueryParam(query.Get("offset"), 0, 0, -1)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid offset: %w", err), w)
+			return
+		}
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		result, err := app.SearchImages(db, query.Get("q"), limit, offset)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		newResponse := searchResponse{
+			Images: make([]searchResponseImage, 0, len(result.Images)),
+			Total:  result.Total,
+		}
+		for _, image := range result.Images {
+			imageUrl := types.ImageUrl(image.Name)
+
+			newResponse.Images = append(newResponse.Images, searchResponseImage{
+				SearchResultImage: image,
+				ThumbnailUrl:      app.GetThumbnailUrl(image.Name),
+				Url:               imageUrl,
+			})
+		}
+
+		sendJSON(app, w, 200, &newResponse)
+	}
+}
+
+// CreateSemanticSearchHandler creates handler for `/api/search/semantic` route.
+func CreateSemanticSearchHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := getIntQueryParam(query.Get("limit"), 50, 1, 1000)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid limit: %w", err), w)
+			return
+		}
+
+		offset, err := getIntQueryParam(query.Get("offset"), 0, 0, -1)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid offset: %w", err), w)
+			return
+		}
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		result, err := app.SearchSemantic(r.Context(), db, query.Get("q"), limit, offset)
+		if errors.Is(err, types.ErrEmbeddingsDisabled) {
+			app.SendHttpErrorWithStatus(503, err, w)
+			return
+		}
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		newResponse := semanticSearchResponse{
+			Images: make([]semanticSearchResponseImage, 0, len(result.Images)),
+		}
+		for _, image := range result.Images {
+			newResponse.Images = append(newResponse.Images, semanticSearchResponseImage{
+				SemanticSearchResultImage: image,
+				ThumbnailUrl:              app.GetThumbnailUrl(image.Name),
+				Url:                       types.ImageUrl(image.Name),
+			})
+		}
+
+		sendJSON(app, w, 200, &newResponse)
+	}
+}
@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package routes
+
+import (
+	"database/sql"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+type getTagsResponse struct {
+	Tags []types.TagUsage `json:"tags"`
+}
+
+// CreateGetTagImagesHandler creates handler for `/api/tags/{tag}/images` route.
+func CreateGetTagImagesHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		tag := strings.Join(strings.Fields(strings.ToLower(vars["tag"])), " ")
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		rows, err := db.Query(`SELECT file_path, title, description, `+types.TagsJSONColumn+`, last_filesize, last_modified, described_at,
+  title_source, description_source, tags_source, title_locked, description_locked, tags_locked
+FROM images
+WHERE id IN (
+  SELECT it.image_id FROM image_tags it
+  INNER JOIN tags t ON t.id = it.tag_id
+  WHERE t.name = ?
+)
+ORDER BY file_path;`, tag)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer rows.Close()
+
+		newResponse := getImageResponse{}
+		newResponse.Images = make([]getImageResponseImage, 0)
+
+		for rows.Next() {
+			var filePath, title, description string
+			var tags, titleSource, descriptionSource, tagsSource sql.NullString
+			var lastFilesize int64
+			var lastModified any
+			var describedAt sql.NullString
+			var locks types.ImageMetaLocks
+			err = rows.Scan(&filePath, &title, &description, &tags, &lastFilesize, &lastModified, &describedAt, &titleSource, &descriptionSource, &tagsSource, &locks.Title, &locks.Description, &locks.Tags)
+			if err != nil {
+				app.SendHttpError(err, w)
+				return
+			}
+
+			_, fullPath, err := app.ResolveImagePath(filePath)
+			if err != nil {
+				continue
+			}
+
+			info, err := os.Stat(fullPath)
+			if err != nil {
+				// file does not exist anymore
+				continue
+			}
+
+			newImage := getImageResponseImage{}
+			newImage.Name = filePath
+			newImage.Status = types.ImageStatusUntagged
+			if describedAt.Valid {
+				newImage.Status = types.GetImageStatusOf(lastFilesize, lastModified, info)
+			}
+			newImage.Url = types.ImageUrl(filePath)
+			newImage.ThumbnailUrl = app.GetThumbnailUrl(newImage.Name)
+			newImage.Info = &getImageResponseImageInfo{
+				Title:       strings.TrimSpace(title),
+				Description: strings.TrimSpace(description),
+				Locks:       locks,
+				Sources:     newImageMetaSources(titleSource, descriptionSource, tagsSource),
+				Tags:        types.ParseTagsJSON(tags),
+			}
+
+			newResponse.Images = append(newResponse.Images, newImage)
+		}
+
+		err = rows.Err()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		sendJSON(app, w, 200, &newResponse)
+	}
+}
+
+// CreateGetTagsHandler creates handler for `/api/tags` route.
+func CreateGetTagsHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		tags, err := app.GetTags(db)
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+
+		sendJSON(app, w, 200, &getTagsResponse{
+			Tags: tags,
+		})
+	}
+}
@@ -0,0 +1,324 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package routes
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+const tusVersion = "1.0.0"
+
+type uploadImagesResponse struct {
+	Images []uploadImagesResponseImage `json:"images"`
+	Job    *types.Job                  `json:"job,omitempty"`
+}
+
+type uploadImagesResponseImage struct {
+	Name         string `json:"name"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+	Url          string `json:"url"`
+}
+
+// CreateUploadImagesHandler creates handler for POST `/api/images` route.
+func CreateUploadImagesHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		folder := query.Get("folder")
+		tag := isTrueParam(query.Get("tag"))
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+
+		response := uploadImagesResponse{
+			Images: make([]uploadImagesResponseImage, 0),
+		}
+
+		// all files are checked, before one is stored
+		images := make([]*types.StagedImage, 0)
+		defer func() {
+			for _, image := range images {
+				image.Remove()
+			}
+		}()
+
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				app.SendHttpErrorWithStatus(400, err, w)
+				return
+			}
+
+			if part.FileName() == "" {
+				// form fields are only used if they are sent before the files
+				value, err := io.ReadAll(io.LimitReader(part, 4096))
+				part.Close()
+				if err != nil {
+					app.SendHttpErrorWithStatus(400, err, w)
+					return
+				}
+
+				switch part.FormName() {
+				case "folder":
+					folder = string(value)
+				case "tag":
+					tag = isTrueParam(string(value))
+				}
+				continue
+			}
+
+			image, err := app.StageImage(part, folder, part.FileName())
+			part.Close()
+			if err != nil {
+				app.SendImageError(err, w)
+				return
+			}
+
+			images = append(images, image)
+		}
+
+		if len(images) == 0 {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("no files uploaded"), w)
+			return
+		}
+
+		names, err := app.StoreStagedImages(images)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
+
+		for _, name := range names {
+			response.Images = append(response.Images, uploadImagesResponseImage{
+				Name:         name,
+				ThumbnailUrl: app.GetThumbnailUrl(name),
+				Url:          types.ImageUrl(name),
+			})
+		}
+
+		if tag {
+			response.Job, err = app.Jobs.CreateJob("tag", types.JobScopeNames, names)
+			if err != nil {
+				app.SendHttpError(err, w)
+				return
+			}
+		}
+
+		sendJSON(app, w, 201, &response)
+	}
+}
+
+// CreateCreateUploadHandler creates handler for POST `/api/uploads` route.
+func CreateCreateUploadHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", tusVersion)
+
+		length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid Upload-Length header"), w)
+			return
+		}
+
+		metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		upload, err := app.CreateUpload(db, metadata["filename"], metadata["folder"], length, isTrueParam(metadata["tag"]))
+		if errors.Is(err, types.ErrUploadTooLarge) {
+			app.SendHttpErrorWithStatus(413, err, w)
+			return
+		}
+		if err != nil {
+			app.SendHttpErrorWithStatus(400, err, w)
+			return
+		}
+
+		w.Header().Set("Location", "/api/uploads/"+upload.Id)
+		sendJSON(app, w, 201, upload)
+	}
+}
+
+// CreateDeleteUploadHandler creates handler for DELETE `/api/uploads/{id}` route.
+func CreateDeleteUploadHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		w.Header().Set("Tus-Resumable", tusVersion)
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		err = app.DeleteUpload(db, vars["id"])
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
+
+		w.WriteHeader(204)
+	}
+}
+
+// CreateGetUploadHandler creates handler for GET and HEAD `/api/uploads/{id}` route.
+func CreateGetUploadHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		w.Header().Set("Tus-Resumable", tusVersion)
+		w.Header().Set("Cache-Control", "no-store")
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		upload, err := app.GetUpload(db, vars["id"])
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
+
+		w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+
+		if r.Method == "HEAD" {
+			w.WriteHeader(200)
+			return
+		}
+
+		sendJSON(app, w, 200, upload)
+	}
+}
+
+// CreateGetUploadOptionsHandler creates handler for OPTIONS `/api/uploads` route.
+func CreateGetUploadOptionsHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", tusVersion)
+		w.Header().Set("Tus-Version", tusVersion)
+		w.Header().Set("Tus-Extension", "creation,termination")
+
+		w.WriteHeader(204)
+	}
+}
+
+// CreatePatchUploadHandler creates handler for PATCH `/api/uploads/{id}` route.
+func CreatePatchUploadHandler(app *types.AppContext) types.HttpHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		w.Header().Set("Tus-Resumable", tusVersion)
+
+		if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+			app.SendHttpErrorWithStatus(415, fmt.Errorf("content type must be 'application/offset+octet-stream'"), w)
+			return
+		}
+
+		offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+		if err != nil || offset < 0 {
+			app.SendHttpErrorWithStatus(400, fmt.Errorf("invalid Upload-Offset header"), w)
+			return
+		}
+
+		db, err := app.OpenImageDatabase()
+		if err != nil {
+			app.SendHttpError(err, w)
+			return
+		}
+		defer db.Close()
+
+		upload, err := app.AppendUploadChunk(db, vars["id"], offset, r.Body)
+		if err != nil {
+			app.SendImageError(err, w)
+			return
+		}
+
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		if upload.FilePath != "" {
+			w.Header().Set("Upload-File-Path", upload.FilePath)
+		}
+
+		w.WriteHeader(204)
+	}
+}
+
+func isTrueParam(value string) bool {
+	switch strings.TrimSpace(strings.ToLower(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+
+	return false
+}
+
+// parseUploadMetadata parses an `Upload-Metadata` header
+// with comma separated `key base64value` pairs.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for metadata '%s'", key)
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+func sendJSON(app *types.AppContext, w http.ResponseWriter, statusCode int, data any) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		app.SendHttpError(err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	w.Write(jsonData)
+}
+
+// serveFile sends a file with ETag, Last-Modified and Cache-Control headers
+// and answers conditional and range requests.
+func serveFile(app *types.AppContext, w http.ResponseWriter, r *http.Request, file *os.File, info os.FileInfo, mimeType string) {
+	header := w.Header()
+	header.Set("Content-Type", mimeType)
+	header.Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	if app.Config.CacheControl != "" {
+		header.Set("Cache-Control", app.Config.CacheControl)
+	}
+
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
+}
+
+func getIntQueryParam(value string, defaultValue int, minValue int, maxValue int) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if i < minValue {
+		return 0, fmt.Errorf("value must be at least %d", minValue)
+	}
+	if maxValue >= minValue && i > maxValue {
+		return 0, fmt.Errorf("value must be at most %d", maxValue)
+	}
+
+	return i, nil
+}
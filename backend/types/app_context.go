@@ -23,62 +23,53 @@
 package types
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
-	"path/filepath"
 
 	"database/sql"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/mkloubert/my-ai-gallery/geocoding"
 )
 
 // AppContext stores information and provides features for handling
 // the current application.
 type AppContext struct {
+	// Config stores the configuration.
+	Config *AppConfig
+	// EmbeddingProvider stores the provider that creates embeddings
+	// or nil, if embeddings are disabled.
+	EmbeddingProvider EmbeddingProvider
 	// EOL the char sequence for new lines.
 	EOL string
+	// Geocoder stores the offline geocoder or nil, if not configured.
+	Geocoder *geocoding.Geocoder
+	// Jobs stores the queue for background jobs.
+	Jobs *JobQueue
 	// Stderr is the standard error stream.
 	Stderr *os.File
 	// Stdout is the standard output stream.
 	Stdout *os.File
+	// VisionProvider stores the provider that describes images.
+	VisionProvider VisionProvider
 	// WorkingDirectory stores the full path of the working directory.
 	WorkingDirectory string
 }
 
 // GetImageFolder returns the full path of the image root folder.
 func (app *AppContext) GetImageFolder() string {
-	return filepath.Join(app.WorkingDirectory, "images")
+	return app.Config.ImageFolder
 }
 
 // OpenImageDatabase open image SQL database.
+// The schema is created and updated by MigrateImageDatabase().
 func (app *AppContext) OpenImageDatabase() (*sql.DB, error) {
-	imageFolder := app.GetImageFolder()
-	databaseFile := filepath.Join(imageFolder, "images.db")
+	databaseFile := app.Config.DatabaseFile
 
-	db, err := sql.Open("sqlite3", databaseFile)
-	if err != nil {
-		return db, err
-	}
-
-	createTable := `CREATE TABLE IF NOT EXISTS images (
-  id INTEGER PRIMARY KEY AUTOINCREMENT,
-  file_path TEXT NOT NULL,
-  last_filesize INTEGER NOT NULL,
-  last_modified DATETIME NOT NULL,
-  title TEXT NOT NULL,
-  description TEXT NOT NULL,
-  tags TEXT NOT NULL,
-  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
-  updated_at DATETIME
-);`
-	_, err = db.Exec(createTable)
-	if err != nil {
-		return db, err
-	}
-
-	createIndex := `CREATE UNIQUE INDEX IF NOT EXISTS idx_images_file_path ON images(file_path);`
-	_, err = db.Exec(createIndex)
+	db, err := sql.Open("sqlite3", "file:"+databaseFile+"?_busy_timeout=10000&_journal_mode=WAL&_foreign_keys=on&_txlock=immediate")
 	if err != nil {
 		return db, err
 	}
@@ -88,13 +79,40 @@ func (app *AppContext) OpenImageDatabase() (*sql.DB, error) {
 
 // SendHttpError sends an error as 500 HTTP response.
 func (app *AppContext) SendHttpError(err error, w http.ResponseWriter) {
+	app.SendHttpErrorWithStatus(500, err, w)
+}
+
+// SendImageError sends an error, which is related to an image path
+// or upload, as 400, 404, 409, 413, 415, 422, 503 or 500 HTTP response.
+func (app *AppContext) SendImageError(err error, w http.ResponseWriter) {
+	if errors.Is(err, ErrInvalidImagePath) || errors.Is(err, ErrInvalidImageMeta) {
+		app.SendHttpErrorWithStatus(400, err, w)
+	} else if errors.Is(err, ErrImageNotFound) || errors.Is(err, ErrUploadNotFound) || errors.Is(err, fs.ErrNotExist) {
+		app.SendHttpErrorWithStatus(404, err, w)
+	} else if errors.Is(err, ErrImageExists) || errors.Is(err, ErrImageNotIndexed) || errors.Is(err, ErrUploadOffsetMismatch) {
+		app.SendHttpErrorWithStatus(409, err, w)
+	} else if errors.Is(err, ErrUploadTooLarge) {
+		app.SendHttpErrorWithStatus(413, err, w)
+	} else if errors.Is(err, ErrUnsupportedMediaType) {
+		app.SendHttpErrorWithStatus(415, err, w)
+	} else if errors.Is(err, ErrImageTooLarge) {
+		app.SendHttpErrorWithStatus(422, err, w)
+	} else if errors.Is(err, ErrEmbeddingsDisabled) {
+		app.SendHttpErrorWithStatus(503, err, w)
+	} else {
+		app.SendHttpError(err, w)
+	}
+}
+
+// SendHttpErrorWithStatus sends an error as HTTP response with a specific status code.
+func (app *AppContext) SendHttpErrorWithStatus(statusCode int, err error, w http.ResponseWriter) {
 	fmt.Fprintf(app.Stderr,
-		"[HTTP ERROR]: %s%s",
-		err.Error(), app.EOL,
+		"[HTTP ERROR %d]: %s%s",
+		statusCode, err.Error(), app.EOL,
 	)
 
-	w.WriteHeader(500)
 	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(statusCode)
 
 	w.Write([]byte(err.Error()))
 }
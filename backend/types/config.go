@@ -0,0 +1,687 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppConfig stores the configuration of the backend.
+//
+// Values are loaded in the following order, where later sources override
+// earlier ones: defaults, YAML file, environment variables, command line flags.
+type AppConfig struct {
+	// CacheControl stores the Cache-Control header for images and thumbnails.
+	// Empty sends no header.
+	CacheControl string `yaml:"cache_control"`
+	// ConfigFile stores the full path of the loaded YAML file, if there is one.
+	ConfigFile string `yaml:"-"`
+	// DatabaseFile stores the path of the SQLite database,
+	// relative to ImageFolder if not absolute.
+	DatabaseFile string `yaml:"database_file"`
+	// Embeddings stores the settings for embeddings and semantic search.
+	Embeddings EmbeddingSettings `yaml:"embeddings"`
+	// Geocoding stores the settings for reverse geocoding.
+	Geocoding GeocodingConfig `yaml:"geocoding"`
+	// ImageFolder stores the path of the image root folder,
+	// relative to the working directory if not absolute.
+	ImageFolder string `yaml:"image_folder"`
+	// Jobs stores the settings for background jobs.
+	Jobs JobsConfig `yaml:"jobs"`
+	// Listen stores the address the HTTP server listens on.
+	Listen string `yaml:"listen"`
+	// Thumbnails stores the settings for thumbnails.
+	Thumbnails ThumbnailsConfig `yaml:"thumbnails"`
+	// Uploads stores the settings for uploads.
+	Uploads UploadsConfig `yaml:"uploads"`
+	// Vision stores the settings for the vision provider.
+	Vision VisionSettings `yaml:"vision"`
+	// Watcher stores the settings for watching the image folder.
+	Watcher WatcherConfig `yaml:"watcher"`
+}
+
+// GeocodingConfig stores the settings for reverse geocoding.
+type GeocodingConfig struct {
+	// CitiesFile stores the path of a GeoNames cities file like `cities15000.txt`,
+	// relative to the working directory if not absolute. Empty disables geocoding.
+	CitiesFile string `yaml:"cities_file"`
+	// MaxDistance stores the maximum distance to the nearest city in kilometers.
+	MaxDistance float64 `yaml:"max_distance"`
+	// Tags stores if city, region and country are added to the AI tags.
+	Tags bool `yaml:"tags"`
+}
+
+// JobsConfig stores the settings for background jobs.
+type JobsConfig struct {
+	// Workers stores the number of parallel workers.
+	Workers int `yaml:"workers"`
+}
+
+// ThumbnailsConfig stores the settings for thumbnails.
+type ThumbnailsConfig struct {
+	// Sizes stores the supported sizes in pixels. The first one is the default.
+	Sizes []int `yaml:"sizes"`
+}
+
+// UploadsConfig stores the settings for uploads.
+type UploadsConfig struct {
+	// Expiration stores how long unfinished uploads are kept without new data. 0 keeps them forever.
+	Expiration time.Duration `yaml:"expiration"`
+	// MaxSize stores the maximum size of an uploaded file in bytes. 0 allows any size.
+	MaxSize int64 `yaml:"max_size"`
+}
+
+// WatcherConfig stores the settings for watching the image folder.
+type WatcherConfig struct {
+	// AutoTag stores if new and changed images are tagged by the AI.
+	AutoTag bool `yaml:"auto_tag"`
+	// Debounce stores how long to wait for more changes, before files are indexed.
+	Debounce time.Duration `yaml:"debounce"`
+	// Enabled stores if the image folder is watched for changes.
+	Enabled bool `yaml:"enabled"`
+	// RescanInterval stores the interval of full scans of the image folder. 0 disables them.
+	RescanInterval time.Duration `yaml:"rescan_interval"`
+}
+
+// ConfigFlags stores the command line flags for an AppConfig.
+type ConfigFlags struct {
+	configFile *string
+	flagSet    *flag.FlagSet
+	values     map[string]*string
+}
+
+type configOption struct {
+	env   string
+	flag  string
+	set   func(config *AppConfig, value string) error
+	usage string
+}
+
+var configOptions = []configOption{
+	{
+		env: "MAIG_LISTEN", flag: "listen", usage: "address the HTTP server listens on",
+		set: func(config *AppConfig, value string) error {
+			config.Listen = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_IMAGE_FOLDER", flag: "image-folder", usage: "path of the image root folder",
+		set: func(config *AppConfig, value string) error {
+			config.ImageFolder = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_DATABASE_FILE", flag: "database-file", usage: "path of the SQLite database, relative to image folder",
+		set: func(config *AppConfig, value string) error {
+			config.DatabaseFile = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDINGS", flag: "embeddings", usage: "create embeddings for semantic search",
+		set: func(config *AppConfig, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			config.Embeddings.Enabled = enabled
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDING_PROVIDER", flag: "embedding-provider", usage: "embedding provider: ollama, openai or fake",
+		set: func(config *AppConfig, value string) error {
+			config.Embeddings.Provider = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDING_URL", flag: "embedding-url", usage: "base URL of the embedding model server",
+		set: func(config *AppConfig, value string) error {
+			config.Embeddings.Url = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDING_API_KEY", flag: "embedding-api-key", usage: "API key for the embedding model server",
+		set: func(config *AppConfig, value string) error {
+			config.Embeddings.ApiKey = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDING_MODEL", flag: "embedding-model", usage: "name of the embedding model",
+		set: func(config *AppConfig, value string) error {
+			config.Embeddings.Model = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDING_TIMEOUT", flag: "embedding-timeout", usage: "maximum duration of a request to the embedding model, like 1m",
+		set: func(config *AppConfig, value string) error {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+
+			config.Embeddings.Timeout = timeout
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDING_IMAGES", flag: "embedding-images", usage: "also create embeddings of the images",
+		set: func(config *AppConfig, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			config.Embeddings.Images = enabled
+			return nil
+		},
+	},
+	{
+		env: "MAIG_EMBEDDING_INDEX", flag: "embedding-index", usage: "index for semantic search: exact or lsh",
+		set: func(config *AppConfig, value string) error {
+			config.Embeddings.Index = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_GEONAMES_FILE", flag: "geonames-file", usage: "path of a GeoNames cities file for reverse geocoding",
+		set: func(config *AppConfig, value string) error {
+			config.Geocoding.CitiesFile = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_GEOCODING_MAX_DISTANCE", flag: "geocoding-max-distance", usage: "maximum distance to the nearest city in kilometers",
+		set: func(config *AppConfig, value string) error {
+			maxDistance, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+
+			config.Geocoding.MaxDistance = maxDistance
+			return nil
+		},
+	},
+	{
+		env: "MAIG_GEOCODING_TAGS", flag: "geocoding-tags", usage: "add city, region and country to the AI tags",
+		set: func(config *AppConfig, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			config.Geocoding.Tags = enabled
+			return nil
+		},
+	},
+	{
+		env: "MAIG_CACHE_CONTROL", flag: "cache-control", usage: "Cache-Control header for images and thumbnails",
+		set: func(config *AppConfig, value string) error {
+			config.CacheControl = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_JOB_WORKERS", flag: "job-workers", usage: "number of workers for background jobs",
+		set: func(config *AppConfig, value string) error {
+			workers, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+
+			config.Jobs.Workers = workers
+			return nil
+		},
+	},
+	{
+		env: "MAIG_THUMBNAIL_SIZES", flag: "thumbnail-sizes", usage: "comma separated list of thumbnail sizes in pixels",
+		set: func(config *AppConfig, value string) error {
+			sizes := make([]int, 0)
+			for _, p := range strings.Split(value, ",") {
+				size, err := strconv.Atoi(strings.TrimSpace(p))
+				if err != nil {
+					return err
+				}
+
+				sizes = append(sizes, size)
+			}
+
+			config.Thumbnails.Sizes = sizes
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_PROVIDER", flag: "vision-provider", usage: "vision provider: ollama, openai or fake",
+		set: func(config *AppConfig, value string) error {
+			config.Vision.Provider = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_URL", flag: "vision-url", usage: "base URL of the model server",
+		set: func(config *AppConfig, value string) error {
+			config.Vision.Url = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_API_KEY", flag: "vision-api-key", usage: "API key for the model server",
+		set: func(config *AppConfig, value string) error {
+			config.Vision.ApiKey = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_IMAGE_MODEL", flag: "vision-model", usage: "name of the vision model",
+		set: func(config *AppConfig, value string) error {
+			config.Vision.Model = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_PROMPT", flag: "vision-prompt", usage: "prompt that is sent with every image",
+		set: func(config *AppConfig, value string) error {
+			config.Vision.Prompt = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_PROMPT_CONTEXT", flag: "vision-prompt-context", usage: "add capture date, GPS position and camera to the prompt",
+		set: func(config *AppConfig, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			config.Vision.PromptContext = enabled
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_CONTEXT_TEMPLATE", flag: "vision-context-template", usage: "Go template for the context block of the prompt",
+		set: func(config *AppConfig, value string) error {
+			config.Vision.ContextTemplate = value
+			return nil
+		},
+	},
+	{
+		env: "MAIG_TAG_MODE", flag: "tag-mode", usage: "how AI tags are saved: replace or merge",
+		set: func(config *AppConfig, value string) error {
+			config.Vision.TagMode = TagMode(value)
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_TEMPERATURE", flag: "vision-temperature", usage: "temperature for the vision model",
+		set: func(config *AppConfig, value string) error {
+			temperature, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+
+			config.Vision.Temperature = temperature
+			return nil
+		},
+	},
+	{
+		env: "MAIG_VISION_TIMEOUT", flag: "vision-timeout", usage: "maximum duration of a request to the vision model, like 5m",
+		set: func(config *AppConfig, value string) error {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+
+			config.Vision.Timeout = timeout
+			return nil
+		},
+	},
+	{
+		env: "MAIG_UPLOAD_EXPIRATION", flag: "upload-expiration", usage: "time unfinished uploads are kept without new data, like 24h, 0 keeps them",
+		set: func(config *AppConfig, value string) error {
+			expiration, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+
+			config.Uploads.Expiration = expiration
+			return nil
+		},
+	},
+	{
+		env: "MAIG_UPLOAD_MAX_SIZE", flag: "upload-max-size", usage: "maximum size of an uploaded file in bytes, 0 allows any size",
+		set: func(config *AppConfig, value string) error {
+			maxSize, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			config.Uploads.MaxSize = maxSize
+			return nil
+		},
+	},
+	{
+		env: "MAIG_WATCHER", flag: "watcher", usage: "watch the image folder and index changes automatically",
+		set: func(config *AppConfig, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			config.Watcher.Enabled = enabled
+			return nil
+		},
+	},
+	{
+		env: "MAIG_WATCHER_AUTO_TAG", flag: "watcher-auto-tag", usage: "tag new and changed images found by the watcher",
+		set: func(config *AppConfig, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			config.Watcher.AutoTag = enabled
+			return nil
+		},
+	},
+	{
+		env: "MAIG_WATCHER_DEBOUNCE", flag: "watcher-debounce", usage: "time to wait for more changes, like 2s",
+		set: func(config *AppConfig, value string) error {
+			debounce, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+
+			config.Watcher.Debounce = debounce
+			return nil
+		},
+	},
+	{
+		env: "MAIG_WATCHER_RESCAN_INTERVAL", flag: "watcher-rescan-interval", usage: "interval of full scans, like 1h, 0 disables them",
+		set: func(config *AppConfig, value string) error {
+			interval, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+
+			config.Watcher.RescanInterval = interval
+			return nil
+		},
+	},
+}
+
+// NewDefaultAppConfig returns a new AppConfig with default values.
+func NewDefaultAppConfig() *AppConfig {
+	return &AppConfig{
+		CacheControl: "public, max-age=86400",
+		DatabaseFile: "images.db",
+		Embeddings: EmbeddingSettings{
+			Index: EmbeddingIndexExact,
+		},
+		Geocoding: GeocodingConfig{
+			MaxDistance: 50,
+			Tags:        true,
+		},
+		ImageFolder: "images",
+		Jobs: JobsConfig{
+			Workers: 2,
+		},
+		Listen: ":8080",
+		Thumbnails: ThumbnailsConfig{
+			Sizes: []int{256, 1024},
+		},
+		Uploads: UploadsConfig{
+			Expiration: 24 * time.Hour,
+			MaxSize:    1 << 30,
+		},
+		Vision: VisionSettings{
+			ContextTemplate: DefaultPromptContextTemplate,
+			Prompt:          "What is in this image?",
+			Provider:        "ollama",
+			TagMode:         TagModeReplace,
+			Temperature:     0.3,
+			Timeout:         5 * time.Minute,
+		},
+		Watcher: WatcherConfig{
+			Debounce:       2 * time.Second,
+			Enabled:        true,
+			RescanInterval: time.Hour,
+		},
+	}
+}
+
+// RegisterConfigFlags registers the flags for an AppConfig in a flag set.
+func RegisterConfigFlags(flagSet *flag.FlagSet) *ConfigFlags {
+	configFlags := &ConfigFlags{
+		configFile: flagSet.String("config", "", "path of the YAML config file (env: MAIG_CONFIG)"),
+		flagSet:    flagSet,
+		values:     map[string]*string{},
+	}
+
+	for _, opt := range configOptions {
+		configFlags.values[opt.flag] = flagSet.String(opt.flag, "", fmt.Sprintf("%s (env: %s)", opt.usage, opt.env))
+	}
+
+	return configFlags
+}
+
+// LoadAppConfig loads, normalizes and validates the configuration.
+// configFlags can be nil.
+func LoadAppConfig(workingDirectory string, configFlags *ConfigFlags) (*AppConfig, error) {
+	config := NewDefaultAppConfig()
+
+	// YAML file
+	configFile := strings.TrimSpace(os.Getenv("MAIG_CONFIG"))
+	if configFlags != nil && strings.TrimSpace(*configFlags.configFile) != "" {
+		configFile = strings.TrimSpace(*configFlags.configFile)
+	}
+
+	mustExist := configFile != ""
+	if configFile == "" {
+		configFile = "maig.yaml"
+	}
+	if !filepath.IsAbs(configFile) {
+		configFile = filepath.Join(workingDirectory, configFile)
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err == nil {
+		err = yaml.Unmarshal(data, config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file '%s': %w", configFile, err)
+		}
+
+		config.ConfigFile = configFile
+	} else if mustExist || !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// environment variables
+	for _, opt := range configOptions {
+		value := strings.TrimSpace(os.Getenv(opt.env))
+		if value == "" {
+			continue
+		}
+
+		err = opt.set(config, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", opt.env, err)
+		}
+	}
+
+	// command line flags
+	if configFlags != nil {
+		var flagErr error
+		configFlags.flagSet.Visit(func(f *flag.Flag) {
+			value, ok := configFlags.values[f.Name]
+			if !ok || flagErr != nil {
+				return
+			}
+
+			for _, opt := range configOptions {
+				if opt.flag == f.Name {
+					err := opt.set(config, strings.TrimSpace(*value))
+					if err != nil {
+						flagErr = fmt.Errorf("invalid value for -%s: %w", f.Name, err)
+					}
+				}
+			}
+		})
+		if flagErr != nil {
+			return nil, flagErr
+		}
+	}
+
+	// normalize paths
+	if !filepath.IsAbs(config.ImageFolder) {
+		config.ImageFolder = filepath.Join(workingDirectory, config.ImageFolder)
+	}
+	if !filepath.IsAbs(config.DatabaseFile) {
+		config.DatabaseFile = filepath.Join(config.ImageFolder, config.DatabaseFile)
+	}
+	if config.Geocoding.CitiesFile != "" && !filepath.IsAbs(config.Geocoding.CitiesFile) {
+		config.Geocoding.CitiesFile = filepath.Join(workingDirectory, config.Geocoding.CitiesFile)
+	}
+
+	// embeddings use the model server of the vision provider by default
+	if strings.TrimSpace(config.Embeddings.Provider) == "" {
+		config.Embeddings.Provider = config.Vision.Provider
+	}
+	if config.Embeddings.Timeout == 0 {
+		config.Embeddings.Timeout = config.Vision.Timeout
+	}
+	if strings.EqualFold(strings.TrimSpace(config.Embeddings.Provider), strings.TrimSpace(config.Vision.Provider)) {
+		if strings.TrimSpace(config.Embeddings.Url) == "" {
+			config.Embeddings.Url = config.Vision.Url
+		}
+		if strings.TrimSpace(config.Embeddings.ApiKey) == "" {
+			config.Embeddings.ApiKey = config.Vision.ApiKey
+		}
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Validate checks the values of the configuration.
+func (config *AppConfig) Validate() error {
+	if strings.TrimSpace(config.Listen) == "" {
+		return fmt.Errorf("listen address must not be empty")
+	}
+
+	info, err := os.Stat(config.ImageFolder)
+	if err != nil {
+		return fmt.Errorf("image folder '%s' is not accessible: %w", config.ImageFolder, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("image folder '%s' is no directory", config.ImageFolder)
+	}
+
+	if config.Geocoding.MaxDistance <= 0 {
+		return fmt.Errorf("maximum geocoding distance must be greater than 0")
+	}
+
+	if config.Jobs.Workers < 1 {
+		return fmt.Errorf("number of job workers must be at least 1")
+	}
+
+	if len(config.Thumbnails.Sizes) == 0 {
+		return fmt.Errorf("at least one thumbnail size is required")
+	}
+	for _, size := range config.Thumbnails.Sizes {
+		if size < 16 || size > 8192 {
+			return fmt.Errorf("thumbnail size %d must be between 16 and 8192", size)
+		}
+	}
+
+	provider := strings.TrimSpace(strings.ToLower(config.Vision.Provider))
+	if provider != "ollama" && provider != "openai" && provider != "fake" {
+		return fmt.Errorf("vision provider '%s' is not supported", config.Vision.Provider)
+	}
+	if strings.TrimSpace(config.Vision.Prompt) == "" {
+		return fmt.Errorf("vision prompt must not be empty")
+	}
+	contextTemplate, err := ParsePromptContextTemplate(config.Vision.ContextTemplate)
+	if err != nil {
+		return err
+	}
+	// find unknown fields early
+	_, err = RenderPromptContext(contextTemplate, NewPromptContext("example.jpg", &ImageMetadata{}))
+	if err != nil {
+		return fmt.Errorf("invalid prompt context template: %w", err)
+	}
+	tagMode, err := ParseTagMode(string(config.Vision.TagMode), TagModeReplace)
+	if err != nil {
+		return err
+	}
+	config.Vision.TagMode = tagMode
+	if config.Vision.Temperature < 0 || config.Vision.Temperature > 2 {
+		return fmt.Errorf("vision temperature must be between 0 and 2")
+	}
+	if config.Vision.Timeout < 0 || config.Embeddings.Timeout < 0 {
+		return fmt.Errorf("timeouts of model servers must not be negative")
+	}
+
+	// checked after the vision provider, which is the default embedding provider
+	embeddingProvider := strings.TrimSpace(strings.ToLower(config.Embeddings.Provider))
+	if embeddingProvider != "ollama" && embeddingProvider != "openai" && embeddingProvider != "fake" {
+		return fmt.Errorf("embedding provider '%s' is not supported", config.Embeddings.Provider)
+	}
+	if config.Embeddings.Index != EmbeddingIndexExact && config.Embeddings.Index != EmbeddingIndexLSH {
+		return fmt.Errorf("embedding index '%s' is not supported", config.Embeddings.Index)
+	}
+
+	if config.Uploads.Expiration < 0 {
+		return fmt.Errorf("upload expiration must not be negative")
+	}
+	if config.Uploads.MaxSize < 0 {
+		return fmt.Errorf("maximum upload size must not be negative")
+	}
+
+	if config.Watcher.Debounce < 100*time.Millisecond {
+		return fmt.Errorf("watcher debounce must be at least 100ms")
+	}
+	if config.Watcher.RescanInterval != 0 && config.Watcher.RescanInterval < time.Minute {
+		return fmt.Errorf("watcher rescan interval must be 0 or at least 1m")
+	}
+
+	return nil
+}
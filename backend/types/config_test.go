@@ -0,0 +1,312 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupConfigTest creates a working directory with an `images` folder
+// and clears all environment variables of the configuration.
+func setupConfigTest(t *testing.T, yaml string) string {
+	t.Helper()
+
+	t.Setenv("MAIG_CONFIG", "")
+	for _, opt := range configOptions {
+		t.Setenv(opt.env, "")
+	}
+
+	workingDirectory := t.TempDir()
+	err := os.Mkdir(filepath.Join(workingDirectory, "images"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if yaml != "" {
+		writeTestFile(t, filepath.Join(workingDirectory, "maig.yaml"), []byte(yaml))
+	}
+
+	return workingDirectory
+}
+
+// parseConfigFlags returns the ConfigFlags of command line arguments.
+func parseConfigFlags(t *testing.T, args ...string) *ConfigFlags {
+	t.Helper()
+
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	configFlags := RegisterConfigFlags(flagSet)
+	err := flagSet.Parse(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return configFlags
+}
+
+func TestLoadAppConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name            string
+		yaml            string
+		env             map[string]string
+		args            []string
+		expectedListen  string
+		expectedWorkers int
+	}{
+		{
+			name:            "defaults",
+			expectedListen:  ":8080",
+			expectedWorkers: 2,
+		},
+		{
+			name:            "YAML",
+			yaml:            "listen: \":9000\"\njobs:\n  workers: 3\n",
+			expectedListen:  ":9000",
+			expectedWorkers: 3,
+		},
+		{
+			name:            "environment variables override YAML",
+			yaml:            "listen: \":9000\"\njobs:\n  workers: 3\n",
+			env:             map[string]string{"MAIG_LISTEN": ":9001"},
+			expectedListen:  ":9001",
+			expectedWorkers: 3,
+		},
+		{
+			name:            "flags override environment variables",
+			yaml:            "listen: \":9000\"\njobs:\n  workers: 3\n",
+			env:             map[string]string{"MAIG_LISTEN": ":9001", "MAIG_JOB_WORKERS": "4"},
+			args:            []string{"-listen", ":9002"},
+			expectedListen:  ":9002",
+			expectedWorkers: 4,
+		},
+		{
+			name:            "empty environment variables are ignored",
+			yaml:            "listen: \":9000\"\n",
+			env:             map[string]string{"MAIG_LISTEN": "  "},
+			expectedListen:  ":9000",
+			expectedWorkers: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workingDirectory := setupConfigTest(t, test.yaml)
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			config, err := LoadAppConfig(workingDirectory, parseConfigFlags(t, test.args...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if config.Listen != test.expectedListen {
+				t.Errorf("got listen %q, expected %q", config.Listen, test.expectedListen)
+			}
+			if config.Jobs.Workers != test.expectedWorkers {
+				t.Errorf("got %d workers, expected %d", config.Jobs.Workers, test.expectedWorkers)
+			}
+		})
+	}
+}
+
+func TestLoadAppConfigFile(t *testing.T) {
+	t.Run("missing default file", func(t *testing.T) {
+		workingDirectory := setupConfigTest(t, "")
+
+		config, err := LoadAppConfig(workingDirectory, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.ConfigFile != "" {
+			t.Errorf("got config file %q, expected none", config.ConfigFile)
+		}
+	})
+
+	t.Run("missing file of flag", func(t *testing.T) {
+		workingDirectory := setupConfigTest(t, "listen: \":9000\"\n")
+
+		_, err := LoadAppConfig(workingDirectory, parseConfigFlags(t, "-config", "missing.yaml"))
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("got error %v, expected %v", err, fs.ErrNotExist)
+		}
+	})
+
+	t.Run("missing file of environment variable", func(t *testing.T) {
+		workingDirectory := setupConfigTest(t, "listen: \":9000\"\n")
+		t.Setenv("MAIG_CONFIG", "missing.yaml")
+
+		_, err := LoadAppConfig(workingDirectory, nil)
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("got error %v, expected %v", err, fs.ErrNotExist)
+		}
+	})
+
+	t.Run("file of flag overrides environment variable", func(t *testing.T) {
+		workingDirectory := setupConfigTest(t, "")
+		writeTestFile(t, filepath.Join(workingDirectory, "env.yaml"), []byte("listen: \":9001\"\n"))
+		writeTestFile(t, filepath.Join(workingDirectory, "flag.yaml"), []byte("listen: \":9002\"\n"))
+		t.Setenv("MAIG_CONFIG", "env.yaml")
+
+		config, err := LoadAppConfig(workingDirectory, parseConfigFlags(t, "-config", "flag.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Listen != ":9002" {
+			t.Errorf("got listen %q, expected %q", config.Listen, ":9002")
+		}
+		if config.ConfigFile != filepath.Join(workingDirectory, "flag.yaml") {
+			t.Errorf("got config file %q", config.ConfigFile)
+		}
+	})
+}
+
+func TestLoadAppConfigPaths(t *testing.T) {
+	tests := []struct {
+		name                 string
+		yaml                 string
+		expectedImageFolder  string
+		expectedDatabaseFile string
+		expectedCitiesFile   string
+	}{
+		{
+			name:                 "defaults",
+			expectedImageFolder:  "images",
+			expectedDatabaseFile: "images/images.db",
+		},
+		{
+			name:                 "relative database file is inside image folder",
+			yaml:                 "database_file: data/gallery.db\ngeocoding:\n  cities_file: geonames/cities15000.txt\n",
+			expectedImageFolder:  "images",
+			expectedDatabaseFile: "images/data/gallery.db",
+			expectedCitiesFile:   "geonames/cities15000.txt",
+		},
+		{
+			name:                 "relative image folder",
+			yaml:                 "image_folder: images/2024\n",
+			expectedImageFolder:  "images/2024",
+			expectedDatabaseFile: "images/2024/images.db",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workingDirectory := setupConfigTest(t, test.yaml)
+			os.MkdirAll(filepath.Join(workingDirectory, "images", "2024"), 0o755)
+
+			config, err := LoadAppConfig(workingDirectory, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if config.ImageFolder != filepath.Join(workingDirectory, test.expectedImageFolder) {
+				t.Errorf("got image folder %q", config.ImageFolder)
+			}
+			if config.DatabaseFile != filepath.Join(workingDirectory, test.expectedDatabaseFile) {
+				t.Errorf("got database file %q", config.DatabaseFile)
+			}
+			if test.expectedCitiesFile != "" && config.Geocoding.CitiesFile != filepath.Join(workingDirectory, test.expectedCitiesFile) {
+				t.Errorf("got cities file %q", config.Geocoding.CitiesFile)
+			}
+		})
+	}
+
+	t.Run("absolute database file", func(t *testing.T) {
+		workingDirectory := setupConfigTest(t, "")
+		databaseFile := filepath.Join(t.TempDir(), "gallery.db")
+		t.Setenv("MAIG_DATABASE_FILE", databaseFile)
+
+		config, err := LoadAppConfig(workingDirectory, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.DatabaseFile != databaseFile {
+			t.Errorf("got database file %q, expected %q", config.DatabaseFile, databaseFile)
+		}
+	})
+}
+
+func TestLoadAppConfigInvalidValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		yaml          string
+		env           map[string]string
+		args          []string
+		expectedError string
+	}{
+		{name: "invalid YAML", yaml: "listen: [", expectedError: "invalid config file"},
+		{name: "missing image folder", yaml: "image_folder: missing\n", expectedError: "is not accessible"},
+		{name: "no workers", yaml: "jobs:\n  workers: 0\n", expectedError: "job workers"},
+		{name: "thumbnail size", env: map[string]string{"MAIG_THUMBNAIL_SIZES": "8"}, expectedError: "thumbnail size"},
+		{name: "vision provider", args: []string{"-vision-provider", "unknown"}, expectedError: "vision provider"},
+		{name: "embedding index", yaml: "embeddings:\n  index: tree\n", expectedError: "embedding index"},
+		{name: "temperature", yaml: "vision:\n  temperature: 3\n", expectedError: "temperature"},
+		{name: "tag mode", env: map[string]string{"MAIG_TAG_MODE": "append"}, expectedError: "append"},
+		{name: "negative timeout", env: map[string]string{"MAIG_VISION_TIMEOUT": "-1s"}, expectedError: "timeouts"},
+		{name: "negative upload size", yaml: "uploads:\n  max_size: -1\n", expectedError: "upload size"},
+		{name: "debounce", yaml: "watcher:\n  debounce: 10ms\n", expectedError: "debounce"},
+		{name: "rescan interval", args: []string{"-watcher-rescan-interval", "30s"}, expectedError: "rescan interval"},
+		{name: "unparsable environment variable", env: map[string]string{"MAIG_JOB_WORKERS": "two"}, expectedError: "MAIG_JOB_WORKERS"},
+		{name: "unparsable flag", args: []string{"-watcher-debounce", "soon"}, expectedError: "-watcher-debounce"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workingDirectory := setupConfigTest(t, test.yaml)
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := LoadAppConfig(workingDirectory, parseConfigFlags(t, test.args...))
+			if err == nil {
+				t.Fatalf("expected error with %q", test.expectedError)
+			}
+			if !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("got error %q, expected %q", err.Error(), test.expectedError)
+			}
+		})
+	}
+}
+
+func TestNewDefaultAppConfigIsValid(t *testing.T) {
+	config := NewDefaultAppConfig()
+	config.ImageFolder = t.TempDir()
+	config.Embeddings.Provider = config.Vision.Provider
+
+	err := config.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Watcher.Debounce != 2*time.Second {
+		t.Errorf("got debounce %v", config.Watcher.Debounce)
+	}
+}
@@ -0,0 +1,153 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/mkloubert/my-ai-gallery/imagehash"
+)
+
+// DuplicateGroup stores images, which look the same.
+type DuplicateGroup struct {
+	// Images stores the images, the preferred ones and then the largest files first.
+	Images []DuplicateImage `json:"images"`
+}
+
+// DuplicateImage is an image of a DuplicateGroup.
+type DuplicateImage struct {
+	// Distance stores the Hamming distance to the hash of the first image of the group.
+	Distance int `json:"distance"`
+	// Filesize stores the size of the file in bytes.
+	Filesize int64 `json:"filesize"`
+	// Hash stores the perceptual hash as hexadecimal string.
+	Hash string `json:"hash"`
+	// Name stores the relative path of the image.
+	Name string `json:"name"`
+	// Preferred is true, if the image has been marked as the copy to keep.
+	Preferred bool `json:"preferred"`
+	// ThumbnailUrl stores the URL of the default thumbnail.
+	ThumbnailUrl string `json:"thumbnail_url"`
+	// Url stores the URL of the image.
+	Url string `json:"url"`
+
+	hash imagehash.Hash
+}
+
+// FindDuplicates groups all indexed images, whose perceptual hashes differ
+// in up to threshold bits. Images are in the same group, if they are
+// connected by such pairs. Only groups with more than one image are returned.
+func (app *AppContext) FindDuplicates(db *sql.DB, threshold int) ([]DuplicateGroup, error) {
+	rows, err := db.Query(`SELECT m.file_path, m.indexed_filesize, m.dhash, m.preferred
+FROM image_metadata m INNER JOIN image_files f ON f.file_path = m.file_path
+WHERE m.dhash IS NOT NULL;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make([]DuplicateImage, 0)
+	for rows.Next() {
+		var image DuplicateImage
+		err := rows.Scan(&image.Name, &image.Filesize, &image.Hash, &image.Preferred)
+		if err != nil {
+			return nil, err
+		}
+
+		image.hash, err = imagehash.Parse(image.Hash)
+		if err != nil {
+			fmt.Fprintf(app.Stderr, "[WARN] Hash of '%s' is invalid: %s%s", image.Name, err.Error(), app.EOL)
+			continue
+		}
+
+		images = append(images, image)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hashes := make([]imagehash.Hash, len(images))
+	for i, image := range images {
+		hashes[i] = image.hash
+	}
+
+	groups := make([]DuplicateGroup, 0)
+	for _, indexes := range imagehash.Group(hashes, threshold) {
+		groupImages := make([]DuplicateImage, len(indexes))
+		for i, index := range indexes {
+			groupImages[i] = images[index]
+		}
+
+		sort.Slice(groupImages, func(i, j int) bool {
+			a, b := groupImages[i], groupImages[j]
+			if a.Preferred != b.Preferred {
+				return a.Preferred
+			}
+			if a.Filesize != b.Filesize {
+				return a.Filesize > b.Filesize
+			}
+			return a.Name < b.Name
+		})
+
+		for i := range groupImages {
+			groupImages[i].Distance = groupImages[0].hash.Distance(groupImages[i].hash)
+			groupImages[i].Url = ImageUrl(groupImages[i].Name)
+			groupImages[i].ThumbnailUrl = app.GetThumbnailUrl(groupImages[i].Name)
+		}
+
+		groups = append(groups, DuplicateGroup{Images: groupImages})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Images[0].Name < groups[j].Images[0].Name
+	})
+
+	return groups, nil
+}
+
+// SetPreferredImage marks an image as the copy of its duplicates, which
+// should be kept, or removes the mark.
+func (app *AppContext) SetPreferredImage(db *sql.DB, imageName string, preferred bool) error {
+	imageName, err := app.resolveImageFile(imageName)
+	if err != nil {
+		return err
+	}
+
+	// indexing is done by scanner, watcher and jobs,
+	// not within a request
+	result, err := db.Exec("UPDATE image_metadata SET preferred = ? WHERE file_path = ?;", preferred, imageName)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: '%s' has no metadata yet", ErrImageNotIndexed, imageName)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build sqlite_fts5
+
+package types_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+func TestSetPreferredImage(t *testing.T) {
+	app, db := newTestApp(t, &tagsVisionProvider{})
+
+	err := app.SetPreferredImage(db, "2024/photo.jpg", true)
+	if !errors.Is(err, types.ErrImageNotIndexed) {
+		t.Errorf("got error %v before indexing, expected %v", err, types.ErrImageNotIndexed)
+	}
+
+	err = app.SetPreferredImage(db, "2024/missing.jpg", true)
+	if !errors.Is(err, types.ErrImageNotFound) {
+		t.Errorf("got error %v for missing image, expected %v", err, types.ErrImageNotFound)
+	}
+
+	_, err = app.IndexImage(db, "2024/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.SetPreferredImage(db, "2024/photo.jpg", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var preferred bool
+	err = db.QueryRow("SELECT preferred FROM image_metadata WHERE file_path = ?;", "2024/photo.jpg").Scan(&preferred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !preferred {
+		t.Error("image has not been marked as preferred")
+	}
+}
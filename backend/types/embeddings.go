@@ -0,0 +1,401 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package types
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/my-ai-gallery/vectors"
+)
+
+const (
+	// EmbeddingIndexExact compares a query with all vectors.
+	EmbeddingIndexExact = "exact"
+	// EmbeddingIndexLSH only compares a query with similar vectors,
+	// which is faster for large libraries, but can miss matches.
+	EmbeddingIndexLSH = "lsh"
+)
+
+const (
+	// EmbeddingKindImage is the embedding of the image itself.
+	EmbeddingKindImage = "image"
+	// EmbeddingKindText is the embedding of title, description and tags.
+	EmbeddingKindText = "text"
+)
+
+// ErrEmbeddingsDisabled is returned, if embeddings are not enabled.
+var ErrEmbeddingsDisabled = errors.New("embeddings are disabled")
+
+// ErrImageEmbeddingsNotSupported is returned by an EmbeddingProvider,
+// which cannot create embeddings of images.
+var ErrImageEmbeddingsNotSupported = errors.New("image embeddings are not supported")
+
+// embeddingIndex caches the search index of the current model,
+// which is loaded again after embeddings have been changed.
+var embeddingIndex struct {
+	index vectors.Index[embeddingKey]
+	mutex sync.Mutex
+}
+
+// EmbeddingProvider is a backend that is able to convert texts
+// and images to vectors.
+type EmbeddingProvider interface {
+	// EmbedImage creates the vector of an image.
+	EmbedImage(ctx context.Context, data []byte, mimeType string) ([]float32, error)
+	// EmbedTexts creates one vector for each text.
+	EmbedTexts(ctx context.Context, texts []string) ([][]float32, error)
+	// Model returns the name of the model.
+	Model() string
+	// Name returns the name of the provider.
+	Name() string
+}
+
+// EmbeddingSettings stores the settings for an embedding provider.
+type EmbeddingSettings struct {
+	// ApiKey stores the optional API key for the model server.
+	ApiKey string `yaml:"api_key"`
+	// Enabled stores if embeddings are created.
+	Enabled bool `yaml:"enabled"`
+	// Images stores if embeddings of images are created, too,
+	// which requires a multimodal model.
+	Images bool `yaml:"images"`
+	// Index stores the search index, `exact` or `lsh`.
+	Index string `yaml:"index"`
+	// Model stores the name of the model.
+	Model string `yaml:"model"`
+	// Provider stores the name of the provider, like `ollama`, `openai` or `fake`.
+	// The vision provider is used by default.
+	Provider string `yaml:"provider"`
+	// Timeout stores the maximum duration of a request to the model server.
+	// The one of the vision provider is used by default.
+	Timeout time.Duration `yaml:"timeout"`
+	// Url stores the base URL of the model server.
+	Url string `yaml:"url"`
+}
+
+// SemanticSearchResult stores the result of a SearchSemantic() call.
+type SemanticSearchResult struct {
+	// Images stores the list of matching images, the most similar first.
+	Images []SemanticSearchResultImage `json:"images"`
+}
+
+// SemanticSearchResultImage is an item of Images property of SemanticSearchResult.
+type SemanticSearchResultImage struct {
+	// Description stores the description of the image.
+	Description string `json:"description"`
+	// Match stores, if the `text` or the `image` embedding matched best.
+	Match string `json:"match"`
+	// Name stores the relative path of the image.
+	Name string `json:"name"`
+	// Score stores the cosine similarity. The higher the value, the better the match.
+	Score float32 `json:"score"`
+	// Tags stores the list of tags.
+	Tags []string `json:"tags"`
+	// Title stores the title of the image.
+	Title string `json:"title"`
+}
+
+type embeddingKey struct {
+	kind string
+	name string
+}
+
+// EmbedImage creates the embeddings of title, description and tags
+// of an image and, if enabled, of the image itself. Embeddings are only
+// created again, if their source or the model has changed.
+func (app *AppContext) EmbedImage(ctx context.Context, db *sql.DB, imageName string) error {
+	if app.EmbeddingProvider == nil {
+		return ErrEmbeddingsDisabled
+	}
+
+	imageName, err := app.resolveImageFile(imageName)
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.GetImageMeta(db, imageName)
+	if err != nil {
+		return err
+	}
+
+	text := ""
+	if meta != nil {
+		text = getEmbeddingText(meta)
+	}
+	if text == "" {
+		err = deleteEmbedding(db, imageName, EmbeddingKindText)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = app.saveEmbedding(db, imageName, EmbeddingKindText, []byte(text), func() ([]float32, error) {
+			vectorList, err := app.EmbeddingProvider.EmbedTexts(ctx, []string{text})
+			if err != nil {
+				return nil, err
+			}
+			if len(vectorList) != 1 {
+				return nil, fmt.Errorf("expected 1 embedding, got %d", len(vectorList))
+			}
+
+			return vectorList[0], nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if app.Config.Embeddings.Images {
+		_, fullPath, err := app.ResolveImagePath(imageName)
+		if err != nil {
+			return err
+		}
+
+		imageData, err := os.ReadFile(fullPath)
+		if err != nil {
+			return err
+		}
+
+		err = app.saveEmbedding(db, imageName, EmbeddingKindImage, imageData, func() ([]float32, error) {
+			return app.EmbeddingProvider.EmbedImage(ctx, imageData, http.DetectContentType(imageData))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SearchSemantic searches for images, whose embeddings are similar to
+// the embedding of input. An image is returned once with its best match.
+func (app *AppContext) SearchSemantic(ctx context.Context, db *sql.DB, input string, limit int, offset int) (*SemanticSearchResult, error) {
+	if app.EmbeddingProvider == nil {
+		return nil, ErrEmbeddingsDisabled
+	}
+
+	result := &SemanticSearchResult{
+		Images: make([]SemanticSearchResultImage, 0),
+	}
+
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return result, nil
+	}
+
+	vectorList, err := app.EmbeddingProvider.EmbedTexts(ctx, []string{input})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectorList) != 1 {
+		return nil, fmt.Errorf("expected 1 embedding, got %d", len(vectorList))
+	}
+	query := vectors.Normalize(vectorList[0])
+
+	index, err := app.getEmbeddingIndex(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// each image can have a text and an image embedding
+	matches := index.Search(query, 2*(offset+limit))
+
+	seen := map[string]bool{}
+	for _, m := range matches {
+		if seen[m.Value.name] {
+			continue
+		}
+		seen[m.Value.name] = true
+
+		if len(seen) <= offset {
+			continue
+		}
+		if len(result.Images) >= limit {
+			break
+		}
+
+		image := SemanticSearchResultImage{
+			Match: m.Value.kind,
+			Name:  m.Value.name,
+			Score: m.Score,
+			Tags:  make([]string, 0),
+		}
+
+		meta, err := app.GetImageMeta(db, m.Value.name)
+		if err != nil {
+			return nil, err
+		}
+		if meta != nil {
+			image.Description = meta.Description
+			image.Tags = meta.Tags
+			image.Title = meta.Title
+		}
+
+		result.Images = append(result.Images, image)
+	}
+
+	return result, nil
+}
+
+func (app *AppContext) getEmbeddingIndex(db *sql.DB) (vectors.Index[embeddingKey], error) {
+	embeddingIndex.mutex.Lock()
+	defer embeddingIndex.mutex.Unlock()
+
+	if embeddingIndex.index != nil {
+		return embeddingIndex.index, nil
+	}
+
+	rows, err := db.Query(
+		"SELECT file_path, kind, vector FROM image_embeddings WHERE model = ? AND file_path IN (SELECT file_path FROM image_files);",
+		app.EmbeddingProvider.Model(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]vectors.Entry[embeddingKey], 0)
+	for rows.Next() {
+		var key embeddingKey
+		var data []byte
+		err = rows.Scan(&key.name, &key.kind, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		vector, err := vectors.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, vectors.Entry[embeddingKey]{Value: key, Vector: vector})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if app.Config.Embeddings.Index == EmbeddingIndexLSH {
+		embeddingIndex.index = vectors.NewLSHIndex(entries, 1)
+	} else {
+		embeddingIndex.index = vectors.NewExactIndex(entries)
+	}
+
+	fmt.Fprintf(app.Stdout, "Loaded %d embeddings into %s index%s", embeddingIndex.index.Size(), app.Config.Embeddings.Index, app.EOL)
+
+	return embeddingIndex.index, nil
+}
+
+// saveEmbedding calls embed and saves the normalized vector, if there
+// is no embedding of source with the current model yet.
+func (app *AppContext) saveEmbedding(db *sql.DB, imageName string, kind string, source []byte, embed func() ([]float32, error)) error {
+	model := app.EmbeddingProvider.Model()
+
+	sum := sha256.Sum256(source)
+	sourceHash := hex.EncodeToString(sum[:])
+
+	var count int
+	err := db.QueryRow(
+		"SELECT COUNT(*) FROM image_embeddings WHERE file_path = ? AND kind = ? AND model = ? AND source_hash = ?;",
+		imageName, kind, model, sourceHash,
+	).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	fmt.Fprintf(app.Stdout, "Creating %s embedding of '%s' with '%s' ...%s", kind, imageName, app.EmbeddingProvider.Name(), app.EOL)
+
+	vector, err := embed()
+	if err != nil {
+		return err
+	}
+	if len(vector) == 0 {
+		return fmt.Errorf("embedding of '%s' is empty", imageName)
+	}
+
+	_, err = db.Exec(`INSERT OR REPLACE INTO image_embeddings (file_path, kind, model, source_hash, vector)
+VALUES (?, ?, ?, ?, ?);`, imageName, kind, model, sourceHash, vectors.Encode(vectors.Normalize(vector)))
+	if err != nil {
+		return err
+	}
+
+	invalidateEmbeddingIndex()
+	return nil
+}
+
+// refreshEmbeddings calls EmbedImage() after the metadata of an image has
+// been changed, if embeddings are enabled. Errors are only logged, because
+// the metadata has already been saved.
+func (app *AppContext) refreshEmbeddings(ctx context.Context, db *sql.DB, imageName string) {
+	if app.EmbeddingProvider == nil {
+		return
+	}
+
+	err := app.EmbedImage(ctx, db, imageName)
+	if err != nil {
+		fmt.Fprintf(app.Stderr, "[WARN] Could not create embeddings of '%s': %s%s", imageName, err.Error(), app.EOL)
+	}
+}
+
+func deleteEmbedding(db *sql.DB, imageName string, kind string) error {
+	result, err := db.Exec("DELETE FROM image_embeddings WHERE file_path = ? AND kind = ?;", imageName, kind)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count > 0 {
+		invalidateEmbeddingIndex()
+	}
+	return nil
+}
+
+func getEmbeddingText(meta *ImageMeta) string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{meta.Title, meta.Description, strings.Join(meta.Tags, ", ")} {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// invalidateEmbeddingIndex removes the cached search index,
+// so that it is loaded again with the next search.
+func invalidateEmbeddingIndex() {
+	embeddingIndex.mutex.Lock()
+	defer embeddingIndex.mutex.Unlock()
+
+	embeddingIndex.index = nil
+}
@@ -0,0 +1,537 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileScanBatchSize is the number of entries, which are saved
+// in one transaction by ScanFiles().
+const fileScanBatchSize = 256
+
+// fileScanMutex serializes calls of ScanFiles().
+var fileScanMutex sync.Mutex
+
+// FileEntry is an entry of the file inventory, which stores
+// the regular files of the image folder.
+type FileEntry struct {
+	// ContentHash stores the SHA-256 hash of the content as hex string.
+	ContentHash string
+	// Height stores the height in pixels, if known.
+	Height *int
+	// MimeType stores the detected MIME type.
+	MimeType string
+	// Modified stores the modification time in UTC.
+	Modified time.Time
+	// Name stores the relative path of the file.
+	Name string
+	// Size stores the size in bytes.
+	Size int64
+	// Width stores the width in pixels, if known.
+	Width *int
+}
+
+// FileScanResult stores the result of a ScanFiles() call.
+type FileScanResult struct {
+	// Added stores the number of new files.
+	Added int
+	// Changed stores the number of changed files.
+	Changed int
+	// Moved stores the number of new files, which have been detected as moved files.
+	Moved int
+	// Removed stores the number of files, which do not exist anymore.
+	Removed int
+	// Unchanged stores the number of files, which have not changed.
+	Unchanged int
+	// Updated stores the relative paths of new and changed files.
+	Updated []string
+}
+
+type fileState struct {
+	deleted  bool
+	hasHash  bool
+	modified any
+	size     int64
+}
+
+// IndexFile updates the inventory entry of a single file and returns it.
+// The file is only read, if it has changed since the last call. A new file
+// with the same content as a deleted one is handled as moved file. If the
+// file does not exist anymore, its entry is marked as deleted.
+func (app *AppContext) IndexFile(db *sql.DB, imageName string) (*FileEntry, error) {
+	cleanName, err := CleanImageName(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	imageName, fullPath, err := app.ResolveImagePath(cleanName)
+	if errors.Is(err, ErrImageNotFound) {
+		return nil, errors.Join(err, markFileDeleted(db, cleanName))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		// removed in the meantime
+		return nil, errors.Join(err, markFileDeleted(db, imageName))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%w: '%s' is no file", ErrImageNotFound, imageName)
+	}
+
+	entry, deleted, err := getFileEntry(db, imageName)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && !deleted && entry.ContentHash != "" && GetImageStatusOf(entry.Size, entry.Modified, info) == ImageStatusFresh {
+		return entry, nil
+	}
+	isNew := entry == nil
+
+	entry, err = readFileEntry(fullPath, imageName, info)
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveFileEntries(db, []*FileEntry{entry})
+	if err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		_, err = app.adoptMovedFile(db, entry)
+		if err != nil {
+			return nil, err
+		}
+	} else if deleted {
+		invalidateEmbeddingIndex()
+	}
+
+	return entry, nil
+}
+
+// ScanFiles updates the file inventory with the files of the image folder.
+// Only the file information is read, except for new or changed files, whose
+// MIME type, dimensions and hash are detected. Entries of files, which do
+// not exist anymore, are marked as deleted and removed after a day, new files
+// with the same content are handled as moved files. Hidden files and folders
+// and the database are ignored.
+func (app *AppContext) ScanFiles(db *sql.DB) (*FileScanResult, error) {
+	fileScanMutex.Lock()
+	defer fileScanMutex.Unlock()
+
+	// the image folder itself can be a symbolic link
+	imageFolder, err := filepath.EvalSymlinks(app.GetImageFolder())
+	if err != nil {
+		return nil, err
+	}
+
+	ignored := app.getDatabaseFiles()
+
+	known, err := loadFileStates(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FileScanResult{
+		Updated: make([]string, 0),
+	}
+	added := make([]*FileEntry, 0)
+	pending := make([]*FileEntry, 0, fileScanBatchSize)
+
+	err = filepath.WalkDir(imageFolder, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if fullPath == imageFolder {
+				return err
+			}
+
+			// ignore unreadable sub items
+			return nil
+		}
+
+		if fullPath == imageFolder {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() || !d.Type().IsRegular() || ignored[fullPath] {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(imageFolder, fullPath)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		state, ok := known[relPath]
+		delete(known, relPath)
+
+		unchanged := ok && !state.deleted && GetImageStatusOf(state.size, state.modified, info) == ImageStatusFresh
+		if unchanged && state.hasHash {
+			result.Unchanged++
+			return nil
+		}
+
+		entry, err := readFileEntry(fullPath, relPath, info)
+		if err != nil {
+			fmt.Fprintf(app.Stderr, "[WARN] Could not read '%s': %s%s", relPath, err.Error(), app.EOL)
+			return nil
+		}
+
+		if unchanged {
+			// only the hash has been missing
+			result.Unchanged++
+		} else {
+			if !ok {
+				added = append(added, entry)
+				result.Added++
+			} else if state.deleted {
+				result.Added++
+			} else {
+				result.Changed++
+			}
+
+			result.Updated = append(result.Updated, relPath)
+		}
+
+		pending = append(pending, entry)
+		if len(pending) == fileScanBatchSize {
+			err = saveFileEntries(db, pending)
+			pending = pending[:0]
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveFileEntries(db, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]string, 0)
+	for name, state := range known {
+		if !state.deleted {
+			removed = append(removed, name)
+		}
+	}
+
+	if len(removed) > 0 {
+		removedJSON, err := json.Marshal(removed)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = db.Exec("UPDATE files SET deleted_at = CURRENT_TIMESTAMP WHERE file_path IN (SELECT value FROM json_each(?));", string(removedJSON))
+		if err != nil {
+			return nil, err
+		}
+
+		result.Removed = len(removed)
+	}
+
+	// moved files are found, after their old entries have been marked
+	for _, entry := range added {
+		oldName, err := app.adoptMovedFile(db, entry)
+		if err != nil {
+			return nil, err
+		}
+		if oldName != "" {
+			result.Added--
+			result.Moved++
+		}
+	}
+
+	_, err = db.Exec("DELETE FROM files WHERE deleted_at < datetime('now', '-1 day');")
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Added > 0 || result.Removed > 0 {
+		// the index only contains existing files
+		invalidateEmbeddingIndex()
+	}
+
+	return result, nil
+}
+
+// adoptMovedFile checks, if a new file has the same content as a file,
+// which has been marked as deleted, and moves title, description, tags,
+// metadata and embeddings of the deleted file to it. Returns the old name
+// or an empty string.
+func (app *AppContext) adoptMovedFile(db *sql.DB, entry *FileEntry) (string, error) {
+	if entry.ContentHash == "" {
+		return "", nil
+	}
+
+	var oldName string
+	err := db.QueryRow(
+		"SELECT file_path FROM files WHERE content_hash = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT 1;",
+		entry.ContentHash,
+	).Scan(&oldName)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	imageOperationsMutex.Lock()
+	defer imageOperationsMutex.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM files WHERE file_path = ?;", oldName)
+	if err != nil {
+		return "", err
+	}
+	err = moveImageRows(tx, oldName, entry.Name)
+	if err != nil {
+		return "", err
+	}
+
+	// the content is the same, so the metadata is still valid
+	modified := entry.Modified.Format(time.RFC3339)
+	_, err = tx.Exec("UPDATE images SET last_filesize = ?, last_modified = ? WHERE file_path = ?;", entry.Size, modified, entry.Name)
+	if err != nil {
+		return "", err
+	}
+	_, err = tx.Exec("UPDATE image_metadata SET indexed_filesize = ?, indexed_modified = ? WHERE file_path = ?;", entry.Size, modified, entry.Name)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return "", err
+	}
+	invalidateEmbeddingIndex()
+
+	fmt.Fprintf(app.Stdout, "Detected move of '%s' to '%s'%s", oldName, entry.Name, app.EOL)
+
+	return oldName, app.RemoveThumbnails(oldName)
+}
+
+// getDatabaseFiles returns the full paths of the database and its
+// temporary files, which are stored in the image folder by default.
+func (app *AppContext) getDatabaseFiles() map[string]bool {
+	files := make(map[string]bool)
+
+	databaseFolder, err := filepath.EvalSymlinks(filepath.Dir(app.Config.DatabaseFile))
+	if err != nil {
+		return files
+	}
+
+	databaseFile := filepath.Join(databaseFolder, filepath.Base(app.Config.DatabaseFile))
+	for _, suffix := range []string{"", "-journal", "-shm", "-wal"} {
+		files[databaseFile+suffix] = true
+	}
+
+	return files
+}
+
+// getFileEntry loads an entry of the file inventory and returns,
+// if it is marked as deleted. Returns nil, if there is none.
+func getFileEntry(db *sql.DB, name string) (*FileEntry, bool, error) {
+	entry := &FileEntry{
+		Name: name,
+	}
+	var modified any
+	var width, height sql.NullInt64
+	var contentHash sql.NullString
+	var deleted bool
+
+	err := db.QueryRow(
+		"SELECT filesize, modified, mime_type, width, height, content_hash, deleted_at IS NOT NULL FROM files WHERE file_path = ?;", name,
+	).Scan(&entry.Size, &modified, &entry.MimeType, &width, &height, &contentHash, &deleted)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry.Modified, _ = toTime(modified)
+	entry.Width = intOrNil(width)
+	entry.Height = intOrNil(height)
+	entry.ContentHash = contentHash.String
+
+	return entry, deleted, nil
+}
+
+func loadFileStates(db *sql.DB) (map[string]fileState, error) {
+	rows, err := db.Query("SELECT file_path, filesize, modified, content_hash IS NOT NULL, deleted_at IS NOT NULL FROM files;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[string]fileState)
+	for rows.Next() {
+		var name string
+		var state fileState
+
+		err = rows.Scan(&name, &state.size, &state.modified, &state.hasHash, &state.deleted)
+		if err != nil {
+			return nil, err
+		}
+
+		states[name] = state
+	}
+
+	return states, rows.Err()
+}
+
+// readFileEntry reads MIME type, dimensions and hash of a file.
+func readFileEntry(fullPath string, name string, info os.FileInfo) (*FileEntry, error) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entry := &FileEntry{
+		Modified: info.ModTime().UTC().Truncate(time.Second),
+		Name:     name,
+		Size:     info.Size(),
+	}
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	entry.MimeType = http.DetectContentType(header[:n])
+
+	if strings.HasPrefix(entry.MimeType, "image/") {
+		_, err = file.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+
+		// unsupported formats have no dimensions
+		config, _, err := image.DecodeConfig(file)
+		if err == nil {
+			entry.Width = &config.Width
+			entry.Height = &config.Height
+		}
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return nil, err
+	}
+	entry.ContentHash = hex.EncodeToString(hash.Sum(nil))
+
+	return entry, nil
+}
+
+// markFileDeleted marks the inventory entry of a file, which does not exist anymore, as deleted.
+func markFileDeleted(db *sql.DB, imageName string) error {
+	result, err := db.Exec("UPDATE files SET deleted_at = CURRENT_TIMESTAMP WHERE file_path = ? AND deleted_at IS NULL;", imageName)
+	if err != nil {
+		return err
+	}
+
+	if count, err := result.RowsAffected(); err == nil && count > 0 {
+		invalidateEmbeddingIndex()
+	}
+
+	return nil
+}
+
+func saveFileEntries(db *sql.DB, entries []*FileEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO files
+(file_path, filesize, modified, mime_type, width, height, content_hash)
+VALUES (?, ?, ?, ?, ?, ?, ?);`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		_, err = stmt.Exec(
+			entry.Name, entry.Size, entry.Modified.Format(time.RFC3339), entry.MimeType,
+			entry.Width, entry.Height, entry.ContentHash,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
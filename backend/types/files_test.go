@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build sqlite_fts5
+
+package types
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIndexFileMarksMissingFiles(t *testing.T) {
+	app := newTestAppContext(t)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "exif.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullPath := filepath.Join(app.GetImageFolder(), "2024", "photo.jpg")
+	writeTestFile(t, fullPath, data)
+
+	err = app.MigrateImageDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	entry, err := app.IndexFile(db, "2024/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.MimeType != "image/jpeg" {
+		t.Errorf("got MIME type %q", entry.MimeType)
+	}
+
+	err = os.Remove(fullPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = app.IndexFile(db, "2024/photo.jpg")
+	if !errors.Is(err, ErrImageNotFound) {
+		t.Errorf("expected ErrImageNotFound, got %v", err)
+	}
+
+	_, deleted, err := getFileEntry(db, "2024/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Error("entry of the removed file is not marked as deleted")
+	}
+}
@@ -0,0 +1,151 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Folder is a node of the folder tree of the image folder.
+type Folder struct {
+	// Children stores the sub folders.
+	Children []*Folder `json:"children"`
+	// ImageCount stores the number of images directly inside this folder.
+	ImageCount int `json:"image_count"`
+	// Name stores the name of the folder.
+	Name string `json:"name"`
+	// Path stores the relative path of the folder.
+	Path string `json:"path"`
+	// TotalImageCount stores the number of images inside this folder and all sub folders.
+	TotalImageCount int `json:"total_image_count"`
+}
+
+// GetFolderTree returns the tree of the image folder with the number
+// of images for each folder, which are counted from the file inventory.
+func (app *AppContext) GetFolderTree(db *sql.DB) (*Folder, error) {
+	imageFolder, err := filepath.EvalSymlinks(app.GetImageFolder())
+	if err != nil {
+		return nil, err
+	}
+
+	root := &Folder{
+		Children: make([]*Folder, 0),
+	}
+	folders := map[string]*Folder{
+		"": root,
+	}
+
+	err = filepath.WalkDir(imageFolder, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || fullPath == imageFolder {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		relPath, err := filepath.Rel(imageFolder, fullPath)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		parent, ok := folders[parentFolderOf(relPath)]
+		if !ok {
+			return filepath.SkipDir
+		}
+
+		folder := &Folder{
+			Children: make([]*Folder, 0),
+			Name:     d.Name(),
+			Path:     relPath,
+		}
+
+		parent.Children = append(parent.Children, folder)
+		folders[relPath] = folder
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT file_path FROM image_files;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+
+		folderPath := parentFolderOf(name)
+
+		if folder, ok := folders[folderPath]; ok {
+			folder.ImageCount++
+		}
+
+		// count in all parents
+		for {
+			if folder, ok := folders[folderPath]; ok {
+				folder.TotalImageCount++
+			}
+
+			if folderPath == "" {
+				break
+			}
+			folderPath = parentFolderOf(folderPath)
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, folder := range folders {
+		sort.Slice(folder.Children, func(i, j int) bool {
+			return strings.ToLower(folder.Children[i].Name) < strings.ToLower(folder.Children[j].Name)
+		})
+	}
+
+	return root, nil
+}
+
+func parentFolderOf(relPath string) string {
+	parent := path.Dir(relPath)
+	if parent == "." {
+		return ""
+	}
+
+	return parent
+}
@@ -0,0 +1,283 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// clusterCellsPerTile is the number of grid cells per map tile side,
+// in which images are clustered.
+const clusterCellsPerTile = 4
+
+// BoundingBox stores a geographic bounding box.
+// MinLongitude is greater than MaxLongitude, if the box crosses the antimeridian.
+type BoundingBox struct {
+	MaxLatitude  float64
+	MaxLongitude float64
+	MinLatitude  float64
+	MinLongitude float64
+}
+
+// GeoFeature is a GeoJSON feature with a point geometry.
+type GeoFeature struct {
+	Geometry   GeoPoint       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+	Type       string         `json:"type"`
+}
+
+// GeoFeatureCollection is a GeoJSON feature collection.
+type GeoFeatureCollection struct {
+	Features []GeoFeature `json:"features"`
+	// Truncated is true, if there are more images or clusters than the limit.
+	Truncated bool   `json:"truncated"`
+	Type      string `json:"type"`
+}
+
+// GeoPoint is a GeoJSON point geometry.
+type GeoPoint struct {
+	// Coordinates stores longitude and latitude.
+	Coordinates [2]float64 `json:"coordinates"`
+	Type        string     `json:"type"`
+}
+
+// GeoQuery stores the parameters for GetGeoFeatures().
+type GeoQuery struct {
+	// BoundingBox stores the visible area or nil for the whole world.
+	BoundingBox *BoundingBox
+	// ClusterMaxZoom is the highest zoom level with clustering.
+	ClusterMaxZoom int
+	// Limit stores the maximum number of images or clusters.
+	Limit int
+	// Zoom stores the zoom level of the map or -1 for no clustering.
+	Zoom int
+}
+
+type geoImage struct {
+	latitude  float64
+	longitude float64
+	name      string
+	takenAt   string
+	title     string
+}
+
+// ParseBoundingBox parses a bounding box in the format
+// `minLon,minLat,maxLon,maxLat`.
+func ParseBoundingBox(value string) (*BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("bbox must have the format 'minLon,minLat,maxLon,maxLat'")
+	}
+
+	values := make([]float64, 4)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid bbox value '%s'", p)
+		}
+		values[i] = v
+	}
+
+	box := &BoundingBox{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
+	}
+	if box.MinLatitude > box.MaxLatitude || box.MinLatitude < -90 || box.MaxLatitude > 90 {
+		return nil, fmt.Errorf("invalid bbox latitudes")
+	}
+	if math.Abs(box.MinLongitude) > 180 || math.Abs(box.MaxLongitude) > 180 {
+		return nil, fmt.Errorf("invalid bbox longitudes")
+	}
+
+	return box, nil
+}
+
+// GetGeoFeatures returns the images with GPS position inside a bounding box
+// as GeoJSON points. Below ClusterMaxZoom, images in the same grid cell are
+// returned as one point with the properties `cluster` and `point_count`.
+func (app *AppContext) GetGeoFeatures(db *sql.DB, query *GeoQuery) (*GeoFeatureCollection, error) {
+	where := "m.gps_latitude IS NOT NULL AND m.gps_longitude IS NOT NULL"
+	args := make([]any, 0)
+
+	if box := query.BoundingBox; box != nil {
+		where += " AND m.gps_latitude BETWEEN ? AND ?"
+		args = append(args, box.MinLatitude, box.MaxLatitude)
+
+		if box.MinLongitude <= box.MaxLongitude {
+			where += " AND m.gps_longitude BETWEEN ? AND ?"
+		} else {
+			where += " AND (m.gps_longitude >= ? OR m.gps_longitude <= ?)"
+		}
+		args = append(args, box.MinLongitude, box.MaxLongitude)
+	}
+
+	collection := &GeoFeatureCollection{
+		Features: make([]GeoFeature, 0),
+		Type:     "FeatureCollection",
+	}
+
+	if query.Zoom >= 0 && query.Zoom <= query.ClusterMaxZoom {
+		return app.getGeoClusters(db, query, collection, where, args)
+	}
+
+	// one more to detect truncation
+	args = append(args, query.Limit+1)
+
+	rows, err := db.Query(`SELECT m.file_path, m.gps_latitude, m.gps_longitude, m.taken_at, images.title
+FROM image_metadata m
+INNER JOIN image_files f ON f.file_path = m.file_path
+LEFT JOIN images ON images.file_path = m.file_path
+WHERE `+where+`
+ORDER BY m.file_path
+LIMIT ?;`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var img geoImage
+		var takenAt, title sql.NullString
+		err = rows.Scan(&img.name, &img.latitude, &img.longitude, &takenAt, &title)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(collection.Features) == query.Limit {
+			collection.Truncated = true
+			break
+		}
+
+		img.takenAt = takenAt.String
+		img.title = title.String
+		collection.Features = append(collection.Features, app.newGeoImageFeature(img))
+	}
+
+	return collection, rows.Err()
+}
+
+// getGeoClusters groups the images of a GeoQuery in grid cells by SQL,
+// so the number of rows is limited by the cells and not by the images.
+func (app *AppContext) getGeoClusters(db *sql.DB, query *GeoQuery, collection *GeoFeatureCollection, where string, args []any) (*GeoFeatureCollection, error) {
+	// the size of a cell in degrees for Web Mercator tiles
+	cellSize := 360 / (math.Pow(2, float64(query.Zoom)) * clusterCellsPerTile)
+
+	// with exactly one MIN() aggregate, SQLite takes the other
+	// columns from the row with the first file path of a cell;
+	// longitude + 180 and latitude + 90 are never negative,
+	// so CAST() works like floor()
+	args = append([]any{cellSize, cellSize}, args...)
+	// one more to detect truncation
+	args = append(args, query.Limit+1)
+
+	rows, err := db.Query(`SELECT
+  CAST((m.gps_longitude + 180) / ? AS INTEGER) AS cell_x,
+  CAST((m.gps_latitude + 90) / ? AS INTEGER) AS cell_y,
+  MIN(m.file_path), m.gps_latitude, m.gps_longitude, m.taken_at, images.title,
+  COUNT(*), AVG(m.gps_latitude), AVG(m.gps_longitude)
+FROM image_metadata m
+INNER JOIN image_files f ON f.file_path = m.file_path
+LEFT JOIN images ON images.file_path = m.file_path
+WHERE `+where+`
+GROUP BY cell_x, cell_y
+ORDER BY cell_x, cell_y
+LIMIT ?;`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cellX, cellY int64
+		var count int
+		var img geoImage
+		var latitude, longitude float64
+		var takenAt, title sql.NullString
+		err = rows.Scan(
+			&cellX, &cellY,
+			&img.name, &img.latitude, &img.longitude, &takenAt, &title,
+			&count, &latitude, &longitude,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(collection.Features) == query.Limit {
+			collection.Truncated = true
+			break
+		}
+
+		if count == 1 {
+			img.takenAt = takenAt.String
+			img.title = title.String
+			collection.Features = append(collection.Features, app.newGeoImageFeature(img))
+			continue
+		}
+
+		collection.Features = append(collection.Features, GeoFeature{
+			Geometry: GeoPoint{
+				Coordinates: [2]float64{longitude, latitude},
+				Type:        "Point",
+			},
+			Properties: map[string]any{
+				"cluster":       true,
+				"point_count":   count,
+				"name":          img.name,
+				"thumbnail_url": app.GetThumbnailUrl(img.name),
+			},
+			Type: "Feature",
+		})
+	}
+
+	return collection, rows.Err()
+}
+
+func (app *AppContext) newGeoImageFeature(img geoImage) GeoFeature {
+	properties := map[string]any{
+		"cluster":       false,
+		"name":          img.name,
+		"thumbnail_url": app.GetThumbnailUrl(img.name),
+		"url":           ImageUrl(img.name),
+	}
+	if img.takenAt != "" {
+		properties["taken_at"] = img.takenAt
+	}
+	if img.title != "" {
+		properties["title"] = img.title
+	}
+
+	return GeoFeature{
+		Geometry: GeoPoint{
+			Coordinates: [2]float64{img.longitude, img.latitude},
+			Type:        "Point",
+		},
+		Properties: properties,
+		Type:       "Feature",
+	}
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build sqlite_fts5
+
+package types_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+// insertGeoImages adds indexed images at the given longitude and latitude.
+func insertGeoImages(t *testing.T, db *sql.DB, positions map[string][2]float64) {
+	t.Helper()
+
+	for name, position := range positions {
+		_, err := db.Exec(
+			"INSERT INTO files (file_path, filesize, modified, mime_type) VALUES (?, 1, CURRENT_TIMESTAMP, 'image/jpeg');",
+			name,
+		)
+		if err == nil {
+			_, err = db.Exec(
+				"INSERT INTO image_metadata (file_path, indexed_filesize, indexed_modified, gps_longitude, gps_latitude) VALUES (?, 1, CURRENT_TIMESTAMP, ?, ?);",
+				name, position[0], position[1],
+			)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetGeoFeatures(t *testing.T) {
+	app, db := newTestApp(t, &tagsVisionProvider{})
+
+	insertGeoImages(t, db, map[string][2]float64{
+		"berlin/1.jpg": {13.40, 52.52},
+		"berlin/2.jpg": {13.41, 52.53},
+		"berlin/3.jpg": {13.42, 52.51},
+		"paris.jpg":    {2.35, 48.86},
+		"rome.jpg":     {12.50, 41.90},
+		"sydney.jpg":   {151.21, -33.87},
+	})
+
+	tests := []struct {
+		name              string
+		query             types.GeoQuery
+		expectedClusters  map[string]int
+		expectedFeatures  int
+		expectedTruncated bool
+	}{
+		{
+			name:             "points",
+			query:            types.GeoQuery{ClusterMaxZoom: 14, Limit: 10, Zoom: -1},
+			expectedFeatures: 6,
+		},
+		{
+			name:              "truncated points",
+			query:             types.GeoQuery{ClusterMaxZoom: 14, Limit: 4, Zoom: -1},
+			expectedFeatures:  4,
+			expectedTruncated: true,
+		},
+		{
+			name:             "clusters",
+			query:            types.GeoQuery{ClusterMaxZoom: 14, Limit: 10, Zoom: 5},
+			expectedClusters: map[string]int{"berlin/1.jpg": 3},
+			expectedFeatures: 4,
+		},
+		{
+			name:              "truncated clusters",
+			query:             types.GeoQuery{ClusterMaxZoom: 14, Limit: 2, Zoom: 5},
+			expectedFeatures:  2,
+			expectedTruncated: true,
+		},
+		{
+			name:             "one cluster for the world",
+			query:            types.GeoQuery{ClusterMaxZoom: 14, Limit: 10, Zoom: 0},
+			expectedClusters: map[string]int{"berlin/1.jpg": 5, "sydney.jpg": 0},
+			expectedFeatures: 2,
+		},
+		{
+			name: "bounding box",
+			query: types.GeoQuery{
+				BoundingBox:    &types.BoundingBox{MinLongitude: 0, MinLatitude: 40, MaxLongitude: 20, MaxLatitude: 50},
+				ClusterMaxZoom: 14,
+				Limit:          10,
+				Zoom:           5,
+			},
+			expectedFeatures: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collection, err := app.GetGeoFeatures(db, &test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(collection.Features) != test.expectedFeatures {
+				t.Errorf("got %d features, expected %d", len(collection.Features), test.expectedFeatures)
+			}
+			if collection.Truncated != test.expectedTruncated {
+				t.Errorf("got truncated %v, expected %v", collection.Truncated, test.expectedTruncated)
+			}
+
+			for _, feature := range collection.Features {
+				count, _ := feature.Properties["point_count"].(int)
+
+				expectedCount, ok := test.expectedClusters[feature.Properties["name"].(string)]
+				if ok && count != expectedCount {
+					t.Errorf("got %d images in cluster of %v, expected %d", count, feature.Properties["name"], expectedCount)
+				}
+				if !ok && count != 0 {
+					t.Errorf("got unexpected cluster of %v with %d images", feature.Properties["name"], count)
+				}
+			}
+		})
+	}
+}
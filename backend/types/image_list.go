@@ -0,0 +1,404 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package types
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// ImageSortDateTaken sorts by capture date or modification time.
+	ImageSortDateTaken = "date_taken"
+	// ImageSortModified sorts by modification time of the file.
+	ImageSortModified = "mtime"
+	// ImageSortName sorts by relative path.
+	ImageSortName = "name"
+	// ImageSortSize sorts by file size.
+	ImageSortSize = "size"
+	// ImageSortUpdatedAt sorts by the last change of title, description, tags or locks.
+	ImageSortUpdatedAt = "updated_at"
+)
+
+// ErrInvalidImageListQuery is returned, if parameters of an ImageListQuery are invalid.
+var ErrInvalidImageListQuery = errors.New("invalid image list query")
+
+// imageSortColumns stores the SQL expressions of the sort keys,
+// which are never NULL.
+var imageSortColumns = map[string]string{
+	ImageSortDateTaken: `COALESCE(substr(m.taken_at, 1, 19), strftime('%Y-%m-%dT%H:%M:%S', f.modified, 'localtime'))`,
+	ImageSortModified:  `strftime('%Y-%m-%dT%H:%M:%S', f.modified)`,
+	ImageSortName:      `f.file_path`,
+	ImageSortSize:      `f.filesize`,
+	ImageSortUpdatedAt: `COALESCE(strftime('%Y-%m-%dT%H:%M:%S', images.updated_at), '')`,
+}
+
+// imageStatusColumn is the SQL expression of the ImageStatus of an image.
+const imageStatusColumn = `CASE
+  WHEN images.id IS NULL OR images.described_at IS NULL THEN 'untagged'
+  WHEN images.last_filesize = f.filesize AND datetime(images.last_modified) = datetime(f.modified) THEN 'fresh'
+  ELSE 'stale'
+END`
+
+// ImageList stores the result of a ListImages() call.
+type ImageList struct {
+	// Images stores the images of the current page.
+	Images []ImageListItem
+	// NextCursor stores the cursor of the next page or is empty for the last page.
+	NextCursor string
+	// Total stores the number of all images, which match the filters.
+	Total int
+}
+
+// ImageListItem is an item of Images property of ImageList.
+type ImageListItem struct {
+	// Exif stores the EXIF and XMP metadata or nil, if there is none.
+	Exif *ImageMetadata
+	// Filesize stores the size of the file in bytes.
+	Filesize int64
+	// Height stores the height in pixels, if known.
+	Height *int
+	// Info stores title, description and tags or nil, if there are none.
+	Info *ImageMeta
+	// MimeType stores the MIME type of the file.
+	MimeType string
+	// Modified stores the modification time of the file in UTC.
+	Modified string
+	// Name stores the relative path of the image.
+	Name string
+	// Status stores the status of the metadata.
+	Status ImageStatus
+	// Width stores the width in pixels, if known.
+	Width *int
+}
+
+// ImageListQuery stores the parameters for ListImages().
+type ImageListQuery struct {
+	// Cursor stores the NextCursor of the previous page.
+	Cursor string
+	// Descending stores if the sort order is reversed.
+	Descending bool
+	// Folder stores the relative path of the folder or is empty for all images.
+	Folder string
+	// HasMetadata only returns images with or without title, description or tags, if not nil.
+	HasMetadata *bool
+	// Limit stores the maximum number of images or 0 for all.
+	Limit int
+	// MimeTypes only returns images with one of these MIME types, if not empty.
+	MimeTypes []string
+	// ModifiedFrom stores the earliest modification time in UTC, as returned by ParseImageListDate().
+	ModifiedFrom string
+	// ModifiedTo stores the latest modification time in UTC, as returned by ParseImageListDate().
+	ModifiedTo string
+	// Recursive stores if images of sub folders of Folder are returned.
+	Recursive bool
+	// Sort stores the sort key, like ImageSortName.
+	Sort string
+	// Statuses only returns images with one of these statuses, if not empty.
+	Statuses []ImageStatus
+	// Tags only returns images, which have all of these tags.
+	Tags []string
+	// TakenFrom stores the earliest capture date, as returned by ParseImageListDate().
+	TakenFrom string
+	// TakenTo stores the latest capture date, as returned by ParseImageListDate().
+	TakenTo string
+}
+
+type imageListCursor struct {
+	Descending bool   `json:"d"`
+	Key        any    `json:"k"`
+	Name       string `json:"n"`
+	Sort       string `json:"s"`
+}
+
+// ListImages returns the images of the file inventory, which match the
+// filters of query, with a single query. Images, which have been added
+// to the image folder, are listed after the next scan.
+func (app *AppContext) ListImages(db *sql.DB, query *ImageListQuery) (*ImageList, error) {
+	sortBy := query.Sort
+	if sortBy == "" {
+		sortBy = ImageSortName
+	}
+	sortColumn, ok := imageSortColumns[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: sort '%s' is not supported", ErrInvalidImageListQuery, sortBy)
+	}
+
+	where := make([]string, 0)
+	args := make([]any, 0)
+
+	if strings.TrimSpace(query.Folder) != "" {
+		folder, fullPath, err := app.ResolveImagePath(query.Folder)
+		if err != nil {
+			return nil, err
+		}
+
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%w: '%s' is no folder", ErrImageNotFound, folder)
+		}
+
+		where = append(where, `f.file_path LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(folder)+"/%")
+
+		if !query.Recursive {
+			where = append(where, "instr(substr(f.file_path, length(?) + 2), '/') = 0")
+			args = append(args, folder)
+		}
+	} else if !query.Recursive {
+		where = append(where, "instr(f.file_path, '/') = 0")
+	}
+
+	if len(query.Statuses) > 0 {
+		where = append(where, imageStatusColumn+" IN ("+placeholders(len(query.Statuses))+")")
+		for _, status := range query.Statuses {
+			args = append(args, status)
+		}
+	}
+
+	if query.HasMetadata != nil {
+		hasMetadata := `(images.id IS NOT NULL AND (images.title <> '' OR images.description <> ''
+  OR EXISTS (SELECT 1 FROM image_tags it WHERE it.image_id = images.id)))`
+		if *query.HasMetadata {
+			where = append(where, hasMetadata)
+		} else {
+			where = append(where, "NOT "+hasMetadata)
+		}
+	}
+
+	for _, tag := range NormalizeTags(query.Tags) {
+		where = append(where, `EXISTS (SELECT 1 FROM image_tags it INNER JOIN tags t ON t.id = it.tag_id
+  WHERE it.image_id = images.id AND t.name = ?)`)
+		args = append(args, tag)
+	}
+
+	if len(query.MimeTypes) > 0 {
+		where = append(where, "f.mime_type IN ("+placeholders(len(query.MimeTypes))+")")
+		for _, mimeType := range query.MimeTypes {
+			args = append(args, mimeType)
+		}
+	}
+
+	// values are compared with the same precision, so `2024-07` includes July
+	addDateRange := func(column string, from string, to string) {
+		if from != "" {
+			where = append(where, "substr("+column+", 1, length(?)) >= ?")
+			args = append(args, from, from)
+		}
+		if to != "" {
+			where = append(where, "substr("+column+", 1, length(?)) <= ?")
+			args = append(args, to, to)
+		}
+	}
+	addDateRange(imageSortColumns[ImageSortDateTaken], query.TakenFrom, query.TakenTo)
+	addDateRange(imageSortColumns[ImageSortModified], query.ModifiedFrom, query.ModifiedTo)
+
+	from := `FROM image_files f
+LEFT JOIN image_metadata m ON m.file_path = f.file_path
+LEFT JOIN images ON images.file_path = f.file_path`
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	result := &ImageList{
+		Images: make([]ImageListItem, 0),
+	}
+
+	err := db.QueryRow("SELECT COUNT(*) "+from+" "+whereClause+";", args...).Scan(&result.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeImageListCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != sortBy || cursor.Descending != query.Descending {
+			return nil, fmt.Errorf("%w: cursor belongs to another sort order", ErrInvalidImageListQuery)
+		}
+
+		cursorCondition := fmt.Sprintf("(%s %s ? OR (%s = ? AND f.file_path %s ?))", sortColumn, comparison, sortColumn, comparison)
+		if whereClause == "" {
+			whereClause = "WHERE " + cursorCondition
+		} else {
+			whereClause += " AND " + cursorCondition
+		}
+		args = append(args, cursor.Key, cursor.Key, cursor.Name)
+	}
+
+	limitClause := ""
+	if query.Limit > 0 {
+		// one more to find out, if there is a next page
+		limitClause = "LIMIT ?"
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := db.Query(`SELECT f.file_path, `+imageStatusColumn+`, f.filesize, f.modified, f.mime_type, f.width, f.height, `+sortColumn+`, `+imageMetadataColumns+`,
+  images.id, images.title, images.description, `+TagsJSONColumn+`, images.title_source, images.description_source, images.tags_source,
+  images.title_locked, images.description_locked, images.tags_locked
+`+from+`
+`+whereClause+`
+ORDER BY `+sortColumn+` `+direction+`, f.file_path `+direction+`
+`+limitClause+`;`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lastKey any
+	for rows.Next() {
+		if query.Limit > 0 && len(result.Images) == query.Limit {
+			cursor, err := encodeImageListCursor(&imageListCursor{
+				Descending: query.Descending,
+				Key:        lastKey,
+				Name:       result.Images[len(result.Images)-1].Name,
+				Sort:       sortBy,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			result.NextCursor = cursor
+			break
+		}
+
+		var item ImageListItem
+		var modified any
+		var width, height sql.NullInt64
+		var metadataRow imageMetadataRow
+		var imageId sql.NullInt64
+		var title, description, tags, titleSource, descriptionSource, tagsSource sql.NullString
+		var titleLocked, descriptionLocked, tagsLocked sql.NullBool
+
+		dest := []any{&item.Name, &item.Status, &item.Filesize, &modified, &item.MimeType, &width, &height, &lastKey}
+		dest = append(dest, metadataRow.pointers()...)
+		dest = append(dest,
+			&imageId, &title, &description, &tags, &titleSource, &descriptionSource, &tagsSource,
+			&titleLocked, &descriptionLocked, &tagsLocked,
+		)
+		err = rows.Scan(dest...)
+		if err != nil {
+			return nil, err
+		}
+
+		if modified, ok := toTime(modified); ok {
+			item.Modified = modified.Format(time.RFC3339)
+		}
+		item.Width = intOrNil(width)
+		item.Height = intOrNil(height)
+
+		exif := metadataRow.toImageMetadata()
+		if !exif.IsEmpty() {
+			item.Exif = exif
+		}
+
+		if imageId.Valid {
+			item.Info = &ImageMeta{
+				Description: strings.TrimSpace(description.String),
+				Locks: ImageMetaLocks{
+					Description: descriptionLocked.Bool,
+					Tags:        tagsLocked.Bool,
+					Title:       titleLocked.Bool,
+				},
+				Name: item.Name,
+				Sources: ImageMetaSources{
+					Description: MetaSource(descriptionSource.String),
+					Tags:        MetaSource(tagsSource.String),
+					Title:       MetaSource(titleSource.String),
+				},
+				Tags:  ParseTagsJSON(tags),
+				Title: strings.TrimSpace(title.String),
+			}
+		}
+
+		result.Images = append(result.Images, item)
+	}
+
+	return result, rows.Err()
+}
+
+// ParseImageListDate checks a date like `2024`, `2024-07`, `2024-07-01`
+// or `2024-07-01T14:30:00` for date filters of an ImageListQuery.
+func ParseImageListDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	for _, layout := range []string{"2006", "2006-01", "2006-01-02", "2006-01-02T15", "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if len(value) == len(layout) {
+			if _, err := time.Parse(layout, value); err == nil {
+				return value, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: invalid date '%s'", ErrInvalidImageListQuery, value)
+}
+
+func decodeImageListCursor(value string) (*imageListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidImageListQuery)
+	}
+
+	var cursor imageListCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.Key == nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidImageListQuery)
+	}
+
+	return &cursor, nil
+}
+
+func encodeImageListCursor(cursor *imageListCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func placeholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", count), ", ")
+}
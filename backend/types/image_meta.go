@@ -0,0 +1,513 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxDescriptionLength is the maximum number of characters of a manual description.
+	MaxDescriptionLength = 10000
+	// MaxTagLength is the maximum number of characters of a manual tag.
+	MaxTagLength = 64
+	// MaxTagCount is the maximum number of manual tags of an image.
+	MaxTagCount = 50
+	// MaxTitleLength is the maximum number of characters of a manual title.
+	MaxTitleLength = 200
+)
+
+// ErrInvalidImageMeta is returned if manual metadata is invalid.
+var ErrInvalidImageMeta = errors.New("invalid image metadata")
+
+// MetaSource describes who wrote a field of the metadata.
+type MetaSource string
+
+const (
+	// MetaSourceAI means that a field has been generated by the vision provider.
+	MetaSourceAI MetaSource = "ai"
+	// MetaSourceManual means that a field has been written by a user.
+	MetaSourceManual MetaSource = "manual"
+	// MetaSourceMixed means that manual tags have been merged with tags of the vision provider.
+	MetaSourceMixed MetaSource = "mixed"
+)
+
+// ImageMeta stores the metadata of an image.
+type ImageMeta struct {
+	// Description stores the description.
+	Description string `json:"description"`
+	// Locks stores which fields are protected from the vision provider.
+	Locks ImageMetaLocks `json:"locks"`
+	// Name stores the relative path of the image.
+	Name string `json:"name"`
+	// Sources stores who wrote the fields.
+	Sources ImageMetaSources `json:"sources"`
+	// Tags stores the normalized tags.
+	Tags []string `json:"tags"`
+	// Title stores the title.
+	Title string `json:"title"`
+}
+
+// ImageMetaInput stores manual metadata for SetImageMeta().
+// Fields, which are nil, are not changed.
+type ImageMetaInput struct {
+	// Description stores the new description.
+	Description *string `json:"description"`
+	// Tags stores the new tags.
+	Tags *[]string `json:"tags"`
+	// Title stores the new title.
+	Title *string `json:"title"`
+}
+
+// ImageMetaLocks stores which fields of the metadata
+// must not be overwritten by the vision provider.
+type ImageMetaLocks struct {
+	// Description stores if the description is locked.
+	Description bool `json:"description"`
+	// Tags stores if the tags are locked.
+	Tags bool `json:"tags"`
+	// Title stores if the title is locked.
+	Title bool `json:"title"`
+}
+
+// ImageMetaLocksInput stores the changes for SetImageMetaLocks().
+// Fields, which are nil, are not changed.
+type ImageMetaLocksInput struct {
+	// Description stores if the description should be locked.
+	Description *bool `json:"description"`
+	// Tags stores if the tags should be locked.
+	Tags *bool `json:"tags"`
+	// Title stores if the title should be locked.
+	Title *bool `json:"title"`
+}
+
+// ImageMetaSources stores who wrote each field of the metadata,
+// which is empty, if the field has never been written.
+type ImageMetaSources struct {
+	// Description stores the source of the description.
+	Description MetaSource `json:"description,omitempty"`
+	// Tags stores the source of the tags.
+	Tags MetaSource `json:"tags,omitempty"`
+	// Title stores the source of the title.
+	Title MetaSource `json:"title,omitempty"`
+}
+
+// ImageMetaUpdate stores the result of an UpdateImageMeta() call.
+type ImageMetaUpdate struct {
+	// FileModificationTime stores the last modification time of the file in RFC3339 format.
+	FileModificationTime string
+	// Filename stores the relative path of the image.
+	Filename string
+	// Filesize stores the size of the file in bytes.
+	Filesize int64
+	// ImageInformation stores the saved information, which keeps
+	// locked fields and contains merged tags.
+	ImageInformation *ImageInformation
+}
+
+// UpdateImageMeta lets the vision provider describe an image
+// and saves the result in db. Locked fields are not changed.
+// An empty tagMode uses the configured one.
+func (app *AppContext) UpdateImageMeta(ctx context.Context, db *sql.DB, imageName string, tagMode TagMode) (*ImageMetaUpdate, error) {
+	if app.VisionProvider == nil {
+		return nil, fmt.Errorf("no vision provider defined")
+	}
+	if tagMode == "" {
+		tagMode = app.Config.Vision.TagMode
+	}
+
+	imageName, fullPath, err := app.ResolveImagePath(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(app.Stdout, "Patching meta of file '%s' ...%s", fullPath, app.EOL)
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// get file size and last update time
+	filesize := info.Size()
+	fileModTime := info.ModTime().UTC().Format(time.RFC3339)
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	imageData, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := http.DetectContentType(imageData)
+
+	fmt.Fprintf(app.Stdout, "Describing file '%s' (%s) with '%s' ...%s", fullPath, mimeType, app.VisionProvider.Name(), app.EOL)
+
+	var fileMetadata *ImageMetadata
+	if app.Config.Vision.PromptContext || app.Config.Geocoding.Tags {
+		fileMetadata, err = app.IndexImage(db, imageName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prompt, err := app.BuildVisionPrompt(imageName, fileMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	imageInformation, err := app.VisionProvider.DescribeImage(ctx, &VisionRequest{
+		Data:     imageData,
+		Filename: imageName,
+		MimeType: mimeType,
+		Prompt:   prompt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(app.Stdout, "Updating new data for file '%s' ...%s", fullPath, app.EOL)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// locked fields keep their values and sources
+	var imageId int64
+	var tagsLocked bool
+	var tagsSource sql.NullString
+	err = tx.QueryRow(`INSERT INTO images
+(file_path, title, description, last_filesize, last_modified, title_source, description_source, tags_source, described_at)
+VALUES (?, ?, ?, ?, ?, 'ai', 'ai', 'ai', CURRENT_TIMESTAMP)
+ON CONFLICT(file_path) DO UPDATE SET
+    title=CASE WHEN images.title_locked THEN images.title ELSE excluded.title END,
+    title_source=CASE WHEN images.title_locked THEN images.title_source ELSE excluded.title_source END,
+    description=CASE WHEN images.description_locked THEN images.description ELSE excluded.description END,
+    description_source=CASE WHEN images.description_locked THEN images.description_source ELSE excluded.description_source END,
+    last_filesize=excluded.last_filesize,
+    last_modified=excluded.last_modified,
+    described_at=CURRENT_TIMESTAMP,
+    updated_at=CURRENT_TIMESTAMP
+RETURNING id, tags_locked, tags_source;`,
+		imageName,
+		strings.TrimSpace(imageInformation.Title),
+		strings.TrimSpace(imageInformation.DetailedDescription),
+		filesize,
+		fileModTime,
+	).Scan(&imageId, &tagsLocked, &tagsSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tagsLocked {
+		tags := imageInformation.Tags
+		if app.Config.Geocoding.Tags && fileMetadata != nil && fileMetadata.Location != nil {
+			tags = append(tags, fileMetadata.Location.Names()...)
+		}
+		newTagsSource := MetaSourceAI
+
+		// AI tags are only added to manual tags, older AI tags are replaced
+		existingSource := MetaSource(tagsSource.String)
+		if tagMode == TagModeMerge && (existingSource == MetaSourceManual || existingSource == MetaSourceMixed) {
+			var existingTags sql.NullString
+			err = tx.QueryRow("SELECT "+TagsJSONColumn+" FROM images WHERE id = ?;", imageId).Scan(&existingTags)
+			if err != nil {
+				return nil, err
+			}
+
+			existingTagList := ParseTagsJSON(existingTags)
+			if len(existingTagList) > 0 {
+				newTagsSource = MetaSourceMixed
+				if len(tags) == 0 {
+					newTagsSource = existingSource
+				}
+			}
+
+			tags = append(existingTagList, tags...)
+		}
+
+		err = SetImageTags(tx, imageId, tags)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec("UPDATE images SET tags_source = ? WHERE id = ?;", newTagsSource, imageId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	app.refreshEmbeddings(ctx, db, imageName)
+
+	meta, err := app.GetImageMeta(db, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	imageInformation = &ImageInformation{
+		DetailedDescription: meta.Description,
+		Tags:                meta.Tags,
+		Title:               meta.Title,
+	}
+
+	return &ImageMetaUpdate{
+		FileModificationTime: fileModTime,
+		Filename:             imageName,
+		Filesize:             filesize,
+		ImageInformation:     imageInformation,
+	}, nil
+}
+
+// GetImageMeta loads the metadata of an image from db.
+// Returns nil, if there is no metadata.
+func (app *AppContext) GetImageMeta(db *sql.DB, imageName string) (*ImageMeta, error) {
+	imageName, err := CleanImageName(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta ImageMeta
+	var tags, titleSource, descriptionSource, tagsSource sql.NullString
+	err = db.QueryRow(`SELECT title, description, `+TagsJSONColumn+`, title_source, description_source, tags_source,
+  title_locked, description_locked, tags_locked
+FROM images WHERE file_path = ?;`, imageName).Scan(
+		&meta.Title, &meta.Description, &tags, &titleSource, &descriptionSource, &tagsSource,
+		&meta.Locks.Title, &meta.Locks.Description, &meta.Locks.Tags,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	meta.Name = imageName
+	meta.Tags = ParseTagsJSON(tags)
+	meta.Sources = ImageMetaSources{
+		Description: MetaSource(descriptionSource.String),
+		Tags:        MetaSource(tagsSource.String),
+		Title:       MetaSource(titleSource.String),
+	}
+
+	return &meta, nil
+}
+
+// SetImageMeta saves metadata, which has been written by a user,
+// and marks the changed fields as manual.
+func (app *AppContext) SetImageMeta(ctx context.Context, db *sql.DB, imageName string, input *ImageMetaInput) (*ImageMeta, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	imageName, fullPath, err := app.ResolveImagePath(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%w: '%s' is no file", ErrImageNotFound, imageName)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the file stays as it is, if it has been described before
+	var imageId int64
+	err = tx.QueryRow(`INSERT INTO images (file_path, title, description, last_filesize, last_modified, described_at)
+VALUES (?, '', '', ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(file_path) DO UPDATE SET described_at=CURRENT_TIMESTAMP, updated_at=CURRENT_TIMESTAMP
+RETURNING id;`,
+		imageName, info.Size(), info.ModTime().UTC().Format(time.RFC3339),
+	).Scan(&imageId)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		_, err = tx.Exec("UPDATE images SET title = ?, title_source = ? WHERE id = ?;",
+			strings.TrimSpace(*input.Title), MetaSourceManual, imageId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if input.Description != nil {
+		_, err = tx.Exec("UPDATE images SET description = ?, description_source = ? WHERE id = ?;",
+			strings.TrimSpace(*input.Description), MetaSourceManual, imageId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if input.Tags != nil {
+		err = SetImageTags(tx, imageId, *input.Tags)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec("UPDATE images SET tags_source = ? WHERE id = ?;", MetaSourceManual, imageId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(app.Stdout, "Saved manual meta of file '%s'%s", imageName, app.EOL)
+
+	app.refreshEmbeddings(ctx, db, imageName)
+
+	return app.GetImageMeta(db, imageName)
+}
+
+// SetImageMetaLocks locks or unlocks fields of the metadata of an image.
+func (app *AppContext) SetImageMetaLocks(db *sql.DB, imageName string, input *ImageMetaLocksInput) (*ImageMeta, error) {
+	if input.Title == nil && input.Description == nil && input.Tags == nil {
+		return nil, fmt.Errorf("%w: no lock to change", ErrInvalidImageMeta)
+	}
+
+	imageName, fullPath, err := app.ResolveImagePath(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%w: '%s' is no file", ErrImageNotFound, imageName)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// fields can be locked before the image has been described,
+	// which keeps it untagged, because described_at is not set
+	_, err = tx.Exec(`INSERT INTO images (file_path, title, description, last_filesize, last_modified)
+VALUES (?, '', '', ?, ?)
+ON CONFLICT(file_path) DO NOTHING;`,
+		imageName, info.Size(), info.ModTime().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE images SET
+    title_locked=COALESCE(?, title_locked),
+    description_locked=COALESCE(?, description_locked),
+    tags_locked=COALESCE(?, tags_locked),
+    updated_at=CURRENT_TIMESTAMP
+WHERE file_path = ?;`,
+		input.Title, input.Description, input.Tags, imageName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return app.GetImageMeta(db, imageName)
+}
+
+// Validate checks the manual metadata.
+func (input *ImageMetaInput) Validate() error {
+	if input.Title == nil && input.Description == nil && input.Tags == nil {
+		return fmt.Errorf("%w: no field to change", ErrInvalidImageMeta)
+	}
+
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" {
+			return fmt.Errorf("%w: title must not be empty", ErrInvalidImageMeta)
+		}
+		if utf8.RuneCountInString(title) > MaxTitleLength {
+			return fmt.Errorf("%w: title must not be longer than %d characters", ErrInvalidImageMeta, MaxTitleLength)
+		}
+		if strings.IndexFunc(title, unicode.IsControl) > -1 {
+			return fmt.Errorf("%w: title must not contain control characters", ErrInvalidImageMeta)
+		}
+	}
+
+	if input.Description != nil {
+		if utf8.RuneCountInString(strings.TrimSpace(*input.Description)) > MaxDescriptionLength {
+			return fmt.Errorf("%w: description must not be longer than %d characters", ErrInvalidImageMeta, MaxDescriptionLength)
+		}
+	}
+
+	if input.Tags != nil {
+		for _, tag := range *input.Tags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				return fmt.Errorf("%w: tags must not be empty", ErrInvalidImageMeta)
+			}
+			if utf8.RuneCountInString(tag) > MaxTagLength {
+				return fmt.Errorf("%w: tag '%s' is longer than %d characters", ErrInvalidImageMeta, tag, MaxTagLength)
+			}
+			if strings.IndexFunc(tag, unicode.IsControl) > -1 {
+				return fmt.Errorf("%w: tag '%s' contains control characters", ErrInvalidImageMeta, tag)
+			}
+		}
+		if len(NormalizeTags(*input.Tags)) > MaxTagCount {
+			return fmt.Errorf("%w: not more than %d tags are allowed", ErrInvalidImageMeta, MaxTagCount)
+		}
+	}
+
+	return nil
+}
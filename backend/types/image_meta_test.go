@@ -0,0 +1,260 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build sqlite_fts5
+
+package types_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mkloubert/my-ai-gallery/providers"
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+// recordingVisionProvider records the requests, which reach the wrapped provider.
+type recordingVisionProvider struct {
+	*providers.FakeProvider
+
+	requests []*types.VisionRequest
+}
+
+func (p *recordingVisionProvider) DescribeImage(ctx context.Context, request *types.VisionRequest) (*types.ImageInformation, error) {
+	p.requests = append(p.requests, request)
+
+	return p.FakeProvider.DescribeImage(ctx, request)
+}
+
+// tagsVisionProvider returns the same title and the current tags for every image.
+type tagsVisionProvider struct {
+	tags []string
+}
+
+func (p *tagsVisionProvider) DescribeImage(ctx context.Context, request *types.VisionRequest) (*types.ImageInformation, error) {
+	return &types.ImageInformation{
+		DetailedDescription: "A test image.",
+		Tags:                p.tags,
+		Title:               "Test",
+	}, nil
+}
+
+func (p *tagsVisionProvider) Name() string {
+	return "tags"
+}
+
+// newTestApp creates an app with a migrated database and a copy
+// of testdata/exif.jpg as `2024/photo.jpg` in its image folder.
+func newTestApp(t *testing.T, provider types.VisionProvider) (*types.AppContext, *sql.DB) {
+	t.Helper()
+
+	imageFolder := t.TempDir()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "exif.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(filepath.Join(imageFolder, "2024"), 0o755)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(imageFolder, "2024", "photo.jpg"), data, 0o644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := types.NewDefaultAppConfig()
+	config.DatabaseFile = filepath.Join(t.TempDir(), "images.db")
+	config.ImageFolder = imageFolder
+
+	app := &types.AppContext{
+		Config:         config,
+		EOL:            "\n",
+		Stderr:         os.Stderr,
+		Stdout:         os.Stdout,
+		VisionProvider: provider,
+	}
+
+	err = app.MigrateImageDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return app, db
+}
+
+func TestUpdateImageMetaPrompt(t *testing.T) {
+	provider := &recordingVisionProvider{FakeProvider: &providers.FakeProvider{}}
+
+	app, db := newTestApp(t, provider)
+	app.Config.Vision.Prompt = "Describe this image."
+	app.Config.Vision.PromptContext = true
+
+	_, err := app.UpdateImageMeta(context.Background(), db, "2024/photo.jpg", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(provider.requests) != 1 {
+		t.Fatalf("got %d requests, expected 1", len(provider.requests))
+	}
+
+	expected := "Describe this image.\n\n" +
+		"Use the following information about the image for title and description, if it fits:\n" +
+		"- taken on Monday, July 1, 2024 at 14:03\n" +
+		"- taken at GPS position 52.52009, -13.40000\n" +
+		"- taken with Canon EOS R5 and RF24-105mm F4 L IS USM"
+	if provider.requests[0].Prompt != expected {
+		t.Errorf("got prompt %q, expected %q", provider.requests[0].Prompt, expected)
+	}
+	if provider.requests[0].Filename != "2024/photo.jpg" {
+		t.Errorf("got filename %q", provider.requests[0].Filename)
+	}
+}
+
+func TestUpdateImageMetaMergesManualTags(t *testing.T) {
+	provider := &tagsVisionProvider{}
+
+	app, db := newTestApp(t, provider)
+	app.Config.Geocoding.Tags = false
+
+	steps := []struct {
+		name           string
+		aiTags         []string
+		manualTags     []string
+		expectedTags   []string
+		expectedSource types.MetaSource
+	}{
+		{
+			name:           "AI tags",
+			aiTags:         []string{"beach", "sunset"},
+			expectedTags:   []string{"beach", "sunset"},
+			expectedSource: types.MetaSourceAI,
+		},
+		{
+			name:           "AI tags replace older AI tags",
+			aiTags:         []string{"sea"},
+			expectedTags:   []string{"sea"},
+			expectedSource: types.MetaSourceAI,
+		},
+		{
+			name:           "manual tags",
+			manualTags:     []string{"holiday"},
+			expectedTags:   []string{"holiday"},
+			expectedSource: types.MetaSourceManual,
+		},
+		{
+			name:           "AI tags are added to manual tags",
+			aiTags:         []string{"sea"},
+			expectedTags:   []string{"holiday", "sea"},
+			expectedSource: types.MetaSourceMixed,
+		},
+		{
+			name:           "AI tags are added to mixed tags",
+			aiTags:         []string{"waves"},
+			expectedTags:   []string{"holiday", "sea", "waves"},
+			expectedSource: types.MetaSourceMixed,
+		},
+	}
+
+	for _, step := range steps {
+		if step.manualTags != nil {
+			_, err := app.SetImageMeta(context.Background(), db, "2024/photo.jpg", &types.ImageMetaInput{Tags: &step.manualTags})
+			if err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			provider.tags = step.aiTags
+
+			_, err := app.UpdateImageMeta(context.Background(), db, "2024/photo.jpg", types.TagModeMerge)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		meta, err := app.GetImageMeta(db, "2024/photo.jpg")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tags := slices.Clone(meta.Tags)
+		slices.Sort(tags)
+		if !slices.Equal(tags, step.expectedTags) {
+			t.Errorf("%s: got tags %v, expected %v", step.name, tags, step.expectedTags)
+		}
+		if meta.Sources.Tags != step.expectedSource {
+			t.Errorf("%s: got source %q, expected %q", step.name, meta.Sources.Tags, step.expectedSource)
+		}
+	}
+}
+
+func TestSearchImagesSkipsDeletedFiles(t *testing.T) {
+	provider := &tagsVisionProvider{tags: []string{"beach"}}
+
+	app, db := newTestApp(t, provider)
+	app.Config.Geocoding.Tags = false
+
+	_, err := app.IndexFile(db, "2024/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = app.UpdateImageMeta(context.Background(), db, "2024/photo.jpg", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := app.SearchImages(db, "beach", 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || len(result.Images) != 1 {
+		t.Fatalf("got %d of %d images before deletion, expected 1", len(result.Images), result.Total)
+	}
+
+	err = os.Remove(filepath.Join(app.GetImageFolder(), "2024", "photo.jpg"))
+	if err == nil {
+		_, err = app.IndexFile(db, "2024/photo.jpg")
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, types.ErrImageNotFound) {
+		t.Fatal(err)
+	}
+
+	result, err = app.SearchImages(db, "beach", 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 0 || len(result.Images) != 0 {
+		t.Errorf("got %d of %d images after deletion, expected none", len(result.Images), result.Total)
+	}
+}
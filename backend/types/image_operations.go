@@ -0,0 +1,282 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// ErrImageExists is returned if the target of an operation already exists.
+var ErrImageExists = errors.New("image already exists")
+
+// imageOperationsMutex serializes operations, which change file paths.
+var imageOperationsMutex sync.Mutex
+
+// DeleteImage deletes an image file with its metadata and thumbnails.
+func (app *AppContext) DeleteImage(db *sql.DB, imageName string) error {
+	imageName, err := app.resolveImageFile(imageName)
+	if err != nil {
+		return err
+	}
+
+	imageOperationsMutex.Lock()
+	defer imageOperationsMutex.Unlock()
+
+	fullPath, err := app.GetImagePath(imageName)
+	if err != nil {
+		return err
+	}
+
+	// move the file away first, so it can be restored if the database fails
+	err = os.MkdirAll(app.GetTrashFolder(), 0o755)
+	if err != nil {
+		return err
+	}
+	trashFile, err := os.CreateTemp(app.GetTrashFolder(), "deleted-*")
+	if err != nil {
+		return err
+	}
+	trashFile.Close()
+
+	err = os.Rename(fullPath, trashFile.Name())
+	if err != nil {
+		os.Remove(trashFile.Name())
+		return err
+	}
+
+	err = app.deleteImageRow(db, imageName)
+	if err != nil {
+		if restoreErr := os.Rename(trashFile.Name(), fullPath); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return err
+	}
+
+	os.Remove(trashFile.Name())
+
+	fmt.Fprintf(app.Stdout, "Deleted image '%s'%s", imageName, app.EOL)
+
+	return app.RemoveThumbnails(imageName)
+}
+
+// GetTrashFolder returns the full path of the folder,
+// where files are kept temporarily while they are deleted.
+func (app *AppContext) GetTrashFolder() string {
+	return filepath.Join(app.GetImageFolder(), ".maig", "trash")
+}
+
+// MoveImage renames or moves an image file inside the image folder
+// and updates its metadata. Returns the new relative path.
+func (app *AppContext) MoveImage(db *sql.DB, imageName string, targetName string) (string, error) {
+	imageName, err := app.resolveImageFile(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	targetName, err = CleanImageName(targetName)
+	if err != nil {
+		return "", err
+	}
+	if targetName == imageName {
+		return imageName, nil
+	}
+
+	imageOperationsMutex.Lock()
+	defer imageOperationsMutex.Unlock()
+
+	_, sourcePath, err := app.ResolveImagePath(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	targetFolder, err := app.ensureImageFolder(path.Dir(targetName))
+	if err != nil {
+		return "", err
+	}
+	targetPath := filepath.Join(targetFolder, path.Base(targetName))
+	if app.isDatabaseFile(targetPath) {
+		return "", fmt.Errorf("%w: '%s' is reserved for the database", ErrInvalidImagePath, targetName)
+	}
+
+	_, _, err = app.ResolveImagePath(targetName)
+	if err == nil {
+		return "", fmt.Errorf("%w: '%s'", ErrImageExists, targetName)
+	}
+	if !errors.Is(err, ErrImageNotFound) {
+		return "", err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM files WHERE file_path = ?;", targetName)
+	if err != nil {
+		return "", err
+	}
+	_, err = tx.Exec("UPDATE files SET file_path = ? WHERE file_path = ?;", targetName, imageName)
+	if err != nil {
+		return "", err
+	}
+	err = moveImageRows(tx, imageName, targetName)
+	if err != nil {
+		return "", err
+	}
+
+	err = renameNoReplace(sourcePath, targetPath)
+	if os.IsExist(err) {
+		return "", fmt.Errorf("%w: '%s'", ErrImageExists, targetName)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		if restoreErr := renameNoReplace(targetPath, sourcePath); restoreErr != nil {
+			return "", errors.Join(err, restoreErr)
+		}
+		return "", err
+	}
+	invalidateEmbeddingIndex()
+
+	fmt.Fprintf(app.Stdout, "Moved image '%s' to '%s'%s", imageName, targetName, app.EOL)
+
+	return targetName, app.RemoveThumbnails(imageName)
+}
+
+// renameWithReservation renames a file after the target has been created
+// exclusively, which works on all file systems, but replaces a target,
+// which has been created between both steps by someone else.
+func renameWithReservation(sourcePath string, targetPath string) error {
+	reservation, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	reservation.Close()
+
+	err = os.Rename(sourcePath, targetPath)
+	if err != nil {
+		os.Remove(targetPath)
+		return err
+	}
+
+	return nil
+}
+
+// resolveImageFile checks that imageName is an existing file
+// inside the image folder and returns its normalized relative path.
+func (app *AppContext) resolveImageFile(imageName string) (string, error) {
+	imageName, fullPath, err := app.ResolveImagePath(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%w: '%s' is no file", ErrImageNotFound, imageName)
+	}
+
+	return imageName, nil
+}
+
+// moveImageRows moves title, description, tags, metadata and embeddings
+// of an image to a new path.
+func moveImageRows(tx *sql.Tx, imageName string, targetName string) error {
+	// a row without file cannot be used anymore
+	_, err := tx.Exec("DELETE FROM images WHERE file_path = ?;", targetName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE images SET file_path = ? WHERE file_path = ?;", targetName, imageName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM image_metadata WHERE file_path = ?;", targetName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE image_metadata SET file_path = ? WHERE file_path = ?;", targetName, imageName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM image_embeddings WHERE file_path = ?;", targetName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE image_embeddings SET file_path = ? WHERE file_path = ?;", targetName, imageName)
+	if err != nil {
+		return err
+	}
+
+	return deleteUnusedTags(tx)
+}
+
+func (app *AppContext) deleteImageRow(db *sql.DB, imageName string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM images WHERE file_path = ?;", imageName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM files WHERE file_path = ?;", imageName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM image_metadata WHERE file_path = ?;", imageName)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM image_embeddings WHERE file_path = ?;", imageName)
+	if err != nil {
+		return err
+	}
+
+	err = deleteUnusedTags(tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	invalidateEmbeddingIndex()
+	return nil
+}
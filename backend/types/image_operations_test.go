@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameNoReplace(t *testing.T) {
+	renames := map[string]func(string, string) error{
+		"renameNoReplace":       renameNoReplace,
+		"renameWithReservation": renameWithReservation,
+	}
+	for name, rename := range renames {
+		t.Run(name, func(t *testing.T) {
+			testRenameNoReplace(t, rename)
+		})
+	}
+}
+
+func testRenameNoReplace(t *testing.T, rename func(string, string) error) {
+	folder := t.TempDir()
+	sourcePath := filepath.Join(folder, "source.jpg")
+	targetPath := filepath.Join(folder, "target.jpg")
+
+	writeTestFile(t, sourcePath, []byte("source"))
+	writeTestFile(t, targetPath, []byte("target"))
+
+	err := rename(sourcePath, targetPath)
+	if !os.IsExist(err) {
+		t.Fatalf("expected an 'exists' error, got %v", err)
+	}
+
+	for fullPath, expected := range map[string]string{sourcePath: "source", targetPath: "target"} {
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("'%s' contains %q, expected %q", fullPath, data, expected)
+		}
+	}
+
+	newPath := filepath.Join(folder, "new.jpg")
+
+	err = rename(sourcePath, newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = os.Lstat(sourcePath)
+	if !os.IsNotExist(err) {
+		t.Errorf("source still exists: %v", err)
+	}
+	data, err := os.ReadFile(newPath)
+	if err != nil || string(data) != "source" {
+		t.Errorf("got %q and %v for the new path", data, err)
+	}
+}
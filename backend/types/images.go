@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ImageStatus describes the state of the AI metadata of an image.
+type ImageStatus string
+
+const (
+	// ImageStatusFresh indicates that the metadata matches the current file.
+	ImageStatusFresh ImageStatus = "fresh"
+	// ImageStatusStale indicates that the file has changed since the metadata has been generated.
+	ImageStatusStale ImageStatus = "stale"
+	// ImageStatusUntagged indicates that there is no metadata for the file.
+	ImageStatusUntagged ImageStatus = "untagged"
+)
+
+// DetectMimeType detects the MIME type of a file by its first 512 bytes.
+func DetectMimeType(fullPath string) (string, error) {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && err.Error() != "EOF" {
+		return "", err
+	}
+
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// GetImageStatusOf compares a stored size and modification time with the
+// current file information.
+func GetImageStatusOf(lastFilesize int64, lastModified any, info os.FileInfo) ImageStatus {
+	if lastFilesize != info.Size() {
+		return ImageStatusStale
+	}
+
+	lastModTime, ok := toTime(lastModified)
+	if !ok || !lastModTime.Equal(info.ModTime().UTC().Truncate(time.Second)) {
+		return ImageStatusStale
+	}
+
+	return ImageStatusFresh
+}
+
+// ImageUrl returns the API URL of an image by its relative path.
+func ImageUrl(imageName string) string {
+	segments := strings.Split(imageName, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+
+	return "/api/images/" + strings.Join(segments, "/")
+}
+
+func toTime(val any) (time.Time, bool) {
+	switch v := val.(type) {
+	case time.Time:
+		return v.UTC().Truncate(time.Second), true
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err == nil {
+			return t.UTC().Truncate(time.Second), true
+		}
+	case []byte:
+		return toTime(string(v))
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,381 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/mkloubert/my-ai-gallery/imagehash"
+	"github.com/mkloubert/my-ai-gallery/metadata"
+)
+
+// ImageLocation stores the place, where an image has been taken.
+type ImageLocation struct {
+	// City stores the name of the nearest city.
+	City string `json:"city"`
+	// Country stores the name of the country.
+	Country string `json:"country"`
+	// CountryCode stores the ISO 3166 code of the country.
+	CountryCode string `json:"country_code"`
+	// Region stores the name of the region, like a state.
+	Region string `json:"region,omitempty"`
+}
+
+// ImageMetadata stores the indexed metadata of an image file.
+type ImageMetadata struct {
+	metadata.Metadata
+	// Location stores the place of the GPS position, if known.
+	Location *ImageLocation `json:"location,omitempty"`
+}
+
+// GetImageMetadata loads the indexed EXIF and XMP metadata of an image.
+// Returns nil, if the image has not been indexed yet.
+func (app *AppContext) GetImageMetadata(db *sql.DB, imageName string) (*ImageMetadata, error) {
+	meta, _, _, err := loadImageMetadata(db, imageName)
+	return meta, err
+}
+
+// IsEmpty returns true, if no metadata has been found.
+func (meta *ImageMetadata) IsEmpty() bool {
+	return meta.Metadata == (metadata.Metadata{}) && meta.Location == nil
+}
+
+// Names returns the non-empty names of city, region and country.
+func (location *ImageLocation) Names() []string {
+	names := make([]string, 0, 3)
+	for _, name := range []string{location.City, location.Region, location.Country} {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// IndexImage extracts the EXIF and XMP metadata of an image and saves
+// it in db, together with its perceptual hash and the place of its GPS
+// position, if a geocoder is available, and updates its entry in the file
+// inventory. The file is only read, if it has changed since the last call.
+func (app *AppContext) IndexImage(db *sql.DB, imageName string) (*ImageMetadata, error) {
+	imageName, fullPath, err := app.ResolveImagePath(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	// keeps the inventory of single files, like uploads, up to date
+	_, err = app.IndexFile(db, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, indexedFilesize, indexedModified, err := loadImageMetadata(db, imageName)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil && GetImageStatusOf(indexedFilesize, indexedModified, info) == ImageStatusFresh {
+		if meta.GPS != nil && meta.Location == nil && app.Geocoder != nil {
+			// the geocoder has not been available before
+			meta.Location = app.lookupLocation(meta.GPS)
+			if meta.Location != nil {
+				err = saveImageLocation(db, imageName, meta.Location)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return meta, nil
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	meta = &ImageMetadata{}
+
+	fileMetadata, err := metadata.Read(file)
+	if err == nil {
+		meta.Metadata = *fileMetadata
+	} else if !errors.Is(err, metadata.ErrUnsupportedFormat) {
+		// the file is remembered anyway, so it is not read again
+		fmt.Fprintf(app.Stderr, "[WARN] Could not read metadata of '%s': %s%s", imageName, err.Error(), app.EOL)
+	}
+
+	var dhash any
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := imagehash.Compute(file)
+	if err == nil {
+		dhash = hash.String()
+	} else if !errors.Is(err, image.ErrFormat) {
+		fmt.Fprintf(app.Stderr, "[WARN] Could not compute hash of '%s': %s%s", imageName, err.Error(), app.EOL)
+	}
+
+	var latitude, longitude, altitude *float64
+	if meta.GPS != nil {
+		latitude = &meta.GPS.Latitude
+		longitude = &meta.GPS.Longitude
+		altitude = meta.GPS.Altitude
+
+		meta.Location = app.lookupLocation(meta.GPS)
+	}
+
+	location := &ImageLocation{}
+	if meta.Location != nil {
+		location = meta.Location
+	}
+
+	_, err = db.Exec(`INSERT OR REPLACE INTO image_metadata
+(file_path, indexed_filesize, indexed_modified, taken_at, camera_make, camera_model, lens_model,
+ exposure_time, f_number, focal_length, iso, orientation, gps_latitude, gps_longitude, gps_altitude,
+ country_code, country, region, city, dhash, preferred)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
+ COALESCE((SELECT preferred FROM image_metadata WHERE file_path = ?), 0));`,
+		imageName, info.Size(), info.ModTime().UTC().Format(time.RFC3339),
+		nullIfEmpty(meta.TakenAt), nullIfEmpty(meta.CameraMake), nullIfEmpty(meta.CameraModel), nullIfEmpty(meta.LensModel),
+		meta.ExposureTime, meta.FNumber, meta.FocalLength, meta.ISO, meta.Orientation,
+		latitude, longitude, altitude,
+		nullIfEmpty(location.CountryCode), nullIfEmpty(location.Country), nullIfEmpty(location.Region), nullIfEmpty(location.City),
+		dhash, imageName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// IndexImages updates the file inventory, indexes the metadata of new
+// and changed images and removes the metadata and embeddings of files,
+// which have been removed from the inventory.
+func (app *AppContext) IndexImages() (*FileScanResult, error) {
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	fmt.Fprintf(app.Stdout, "Scanning image folder ...%s", app.EOL)
+
+	scan, err := app.ScanFiles(db)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(app.Stdout, "Found %d new, %d changed, %d moved and %d removed files%s", scan.Added, scan.Changed, scan.Moved, scan.Removed, app.EOL)
+
+	names, err := listImagesToIndex(db, app.Geocoder != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(app.Stdout, "Indexing %d images ...%s", len(names), app.EOL)
+
+	for _, name := range names {
+		_, err := app.IndexImage(db, name)
+		if err != nil {
+			fmt.Fprintf(app.Stderr, "[WARN] Could not index '%s': %s%s", name, err.Error(), app.EOL)
+		}
+	}
+
+	result, err := db.Exec("DELETE FROM image_metadata WHERE file_path NOT IN (SELECT file_path FROM files);")
+	if err != nil {
+		return nil, err
+	}
+
+	removed, _ := result.RowsAffected()
+
+	result, err = db.Exec("DELETE FROM image_embeddings WHERE file_path NOT IN (SELECT file_path FROM files);")
+	if err != nil {
+		return nil, err
+	}
+	if count, _ := result.RowsAffected(); count > 0 {
+		invalidateEmbeddingIndex()
+	}
+	fmt.Fprintf(app.Stdout, "Indexed %d images, removed %d old entries%s", len(names), removed, app.EOL)
+
+	return scan, nil
+}
+
+func (app *AppContext) lookupLocation(position *metadata.GPSPosition) *ImageLocation {
+	if app.Geocoder == nil {
+		return nil
+	}
+
+	place := app.Geocoder.Lookup(position.Latitude, position.Longitude)
+	if place == nil {
+		return nil
+	}
+
+	return &ImageLocation{
+		City:        place.City,
+		Country:     place.Country,
+		CountryCode: place.CountryCode,
+		Region:      place.Region,
+	}
+}
+
+// imageMetadataColumns are the columns of image_metadata with alias `m`,
+// which are read by imageMetadataRow.
+const imageMetadataColumns = `m.indexed_filesize, m.indexed_modified, m.taken_at, m.camera_make, m.camera_model, m.lens_model,
+  m.exposure_time, m.f_number, m.focal_length, m.iso, m.orientation, m.gps_latitude, m.gps_longitude, m.gps_altitude,
+  m.country_code, m.country, m.region, m.city`
+
+// imageMetadataRow stores the values of imageMetadataColumns.
+type imageMetadataRow struct {
+	indexedFilesize                                                   sql.NullInt64
+	indexedModified                                                   any
+	takenAt, cameraMake, cameraModel, lensModel                       sql.NullString
+	countryCode, country, region, city                                sql.NullString
+	exposureTime, fNumber, focalLength, latitude, longitude, altitude sql.NullFloat64
+	iso, orientation                                                  sql.NullInt64
+}
+
+// listImagesToIndex returns the images of the inventory, whose metadata
+// is missing or outdated. withLocations also returns images with a GPS
+// position, but without place.
+func listImagesToIndex(db *sql.DB, withLocations bool) ([]string, error) {
+	query := `SELECT f.file_path FROM image_files f LEFT JOIN image_metadata m ON m.file_path = f.file_path
+WHERE m.file_path IS NULL OR m.indexed_filesize <> f.filesize OR datetime(m.indexed_modified) <> datetime(f.modified)`
+	if withLocations {
+		query += " OR (m.gps_latitude IS NOT NULL AND m.country_code IS NULL)"
+	}
+
+	rows, err := db.Query(query + " ORDER BY f.file_path;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+func loadImageMetadata(db *sql.DB, imageName string) (*ImageMetadata, int64, any, error) {
+	var row imageMetadataRow
+	err := db.QueryRow("SELECT "+imageMetadataColumns+" FROM image_metadata m WHERE m.file_path = ?;", imageName).Scan(row.pointers()...)
+	if err == sql.ErrNoRows {
+		return nil, 0, nil, nil
+	}
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return row.toImageMetadata(), row.indexedFilesize.Int64, row.indexedModified, nil
+}
+
+func (row *imageMetadataRow) pointers() []any {
+	return []any{
+		&row.indexedFilesize, &row.indexedModified, &row.takenAt, &row.cameraMake, &row.cameraModel, &row.lensModel,
+		&row.exposureTime, &row.fNumber, &row.focalLength, &row.iso, &row.orientation, &row.latitude, &row.longitude, &row.altitude,
+		&row.countryCode, &row.country, &row.region, &row.city,
+	}
+}
+
+func (row *imageMetadataRow) toImageMetadata() *ImageMetadata {
+	var meta ImageMetadata
+
+	meta.TakenAt = row.takenAt.String
+	meta.CameraMake = row.cameraMake.String
+	meta.CameraModel = row.cameraModel.String
+	meta.LensModel = row.lensModel.String
+	meta.ExposureTime = floatOrNil(row.exposureTime)
+	meta.FNumber = floatOrNil(row.fNumber)
+	meta.FocalLength = floatOrNil(row.focalLength)
+	meta.ISO = intOrNil(row.iso)
+	meta.Orientation = intOrNil(row.orientation)
+	if row.latitude.Valid && row.longitude.Valid {
+		meta.GPS = &metadata.GPSPosition{
+			Altitude:  floatOrNil(row.altitude),
+			Latitude:  row.latitude.Float64,
+			Longitude: row.longitude.Float64,
+		}
+	}
+
+	if row.countryCode.Valid {
+		meta.Location = &ImageLocation{
+			City:        row.city.String,
+			Country:     row.country.String,
+			CountryCode: row.countryCode.String,
+			Region:      row.region.String,
+		}
+	}
+
+	return &meta
+}
+
+func saveImageLocation(db *sql.DB, imageName string, location *ImageLocation) error {
+	_, err := db.Exec(
+		"UPDATE image_metadata SET country_code = ?, country = ?, region = ?, city = ? WHERE file_path = ?;",
+		location.CountryCode, location.Country, nullIfEmpty(location.Region), location.City, imageName,
+	)
+	return err
+}
+
+func floatOrNil(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
+
+func intOrNil(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	i := int(value.Int64)
+	return &i
+}
+
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
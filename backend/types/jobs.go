@@ -0,0 +1,545 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JobActionFunc is a function that processes a single file of a job.
+type JobActionFunc = func(app *AppContext, db *sql.DB, filePath string) error
+
+// JobStatus describes the state of a job or one of its items.
+type JobStatus string
+
+const (
+	// JobStatusDone indicates that a job or item has been processed.
+	JobStatusDone JobStatus = "done"
+	// JobStatusFailed indicates that an item could not be processed
+	// or that all items of a job have failed.
+	JobStatusFailed JobStatus = "failed"
+	// JobStatusPending indicates that a job or item is waiting for a worker.
+	JobStatusPending JobStatus = "pending"
+	// JobStatusRunning indicates that a job or item is currently processed.
+	JobStatusRunning JobStatus = "running"
+)
+
+// ErrInvalidJob is returned by CreateJob(), if action or scope are not supported.
+var ErrInvalidJob = errors.New("invalid job")
+
+const (
+	// JobScopeAll selects all image files.
+	JobScopeAll = "all"
+	// JobScopeNames selects an explicit list of image files.
+	JobScopeNames = "names"
+	// JobScopeStale selects all image files with stale metadata.
+	JobScopeStale = "stale"
+	// JobScopeUntagged selects all image files without metadata.
+	JobScopeUntagged = "untagged"
+)
+
+// Job stores the data of a job.
+type Job struct {
+	// Action stores the name of the action, like `tag`.
+	Action string `json:"action"`
+	// Counts stores the number of items by status.
+	Counts JobCounts `json:"counts"`
+	// CreatedAt stores the time the job has been created.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// FinishedAt stores the time the last item has been processed.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Id stores the ID of the job.
+	Id int64 `json:"id"`
+	// Items stores the list of items, if loaded.
+	Items []JobItem `json:"items,omitempty"`
+	// Scope stores the scope the items have been selected by.
+	Scope string `json:"scope"`
+	// StartedAt stores the time the first item has been started.
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// Status stores the current status.
+	Status JobStatus `json:"status"`
+}
+
+// JobCounts stores the number of items of a job by status.
+type JobCounts struct {
+	// Done stores the number of successfully processed items.
+	Done int `json:"done"`
+	// Failed stores the number of failed items.
+	Failed int `json:"failed"`
+	// Pending stores the number of waiting items.
+	Pending int `json:"pending"`
+	// Running stores the number of items in progress.
+	Running int `json:"running"`
+	// Total stores the total number of items.
+	Total int `json:"total"`
+}
+
+// JobItem stores the data of a single file of a job.
+type JobItem struct {
+	// Error stores the error message, if failed.
+	Error string `json:"error,omitempty"`
+	// Name stores the relative path of the file.
+	Name string `json:"name"`
+	// Status stores the current status.
+	Status JobStatus `json:"status"`
+}
+
+// JobQueue processes jobs, which are stored in the image database,
+// with a bounded pool of workers.
+type JobQueue struct {
+	actions map[string]JobActionFunc
+	app     *AppContext
+	db      *sql.DB
+	notify  chan struct{}
+	workers int
+}
+
+// NewJobQueue creates a new JobQueue with a specific number of workers.
+func NewJobQueue(app *AppContext, workers int) *JobQueue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &JobQueue{
+		actions: map[string]JobActionFunc{
+			"embed": func(app *AppContext, db *sql.DB, filePath string) error {
+				return app.EmbedImage(context.Background(), db, filePath)
+			},
+			"index": func(app *AppContext, db *sql.DB, filePath string) error {
+				_, err := app.IndexImage(db, filePath)
+				return err
+			},
+			"tag": func(app *AppContext, db *sql.DB, filePath string) error {
+				// also updates the embeddings
+				_, err := app.UpdateImageMeta(context.Background(), db, filePath, "")
+				return err
+			},
+			"thumbnails": func(app *AppContext, db *sql.DB, filePath string) error {
+				for _, size := range app.Config.Thumbnails.Sizes {
+					_, err := app.GetThumbnail(filePath, size)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		app:     app,
+		notify:  make(chan struct{}, workers),
+		workers: workers,
+	}
+}
+
+// CreateJob creates a new job for an action and a scope.
+// names is only used, if scope is `names`.
+func (q *JobQueue) CreateJob(action string, scope string, names []string) (*Job, error) {
+	action = strings.TrimSpace(strings.ToLower(action))
+	if action == "" {
+		action = "tag"
+	}
+	if _, ok := q.actions[action]; !ok {
+		return nil, fmt.Errorf("%w: action '%s' is not supported", ErrInvalidJob, action)
+	}
+
+	scope = strings.TrimSpace(strings.ToLower(scope))
+	if scope == "" {
+		scope = JobScopeNames
+	}
+
+	if action == "index" && scope != JobScopeNames {
+		// new files are only known after a scan
+		_, err := q.app.ScanFiles(q.db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fileNames, err := q.resolveScope(scope, names)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := q.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO jobs (action, scope, status) VALUES (?, ?, ?);", action, scope, JobStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	jobId, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO job_items (job_id, file_path, status) VALUES (?, ?, ?);")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	for _, name := range fileNames {
+		_, err = stmt.Exec(jobId, name, JobStatusPending)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(fileNames) == 0 {
+		_, err = tx.Exec("UPDATE jobs SET status = ?, finished_at = CURRENT_TIMESTAMP WHERE id = ?;", JobStatusDone, jobId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(q.app.Stdout, "Created job %d (%s/%s) with %d item(s)%s", jobId, action, scope, len(fileNames), q.app.EOL)
+
+	q.wakeUp()
+
+	return q.GetJob(jobId, false)
+}
+
+// GetJob loads a job by its ID. Returns nil if not found.
+func (q *JobQueue) GetJob(id int64, withItems bool) (*Job, error) {
+	row := q.db.QueryRow("SELECT id, action, scope, status, created_at, started_at, finished_at FROM jobs WHERE id = ?;", id)
+
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.loadCounts(job)
+	if err != nil {
+		return nil, err
+	}
+
+	if withItems {
+		rows, err := q.db.Query("SELECT file_path, status, error FROM job_items WHERE job_id = ? ORDER BY id;", id)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		job.Items = make([]JobItem, 0)
+		for rows.Next() {
+			var item JobItem
+			var errorMessage sql.NullString
+			err = rows.Scan(&item.Name, &item.Status, &errorMessage)
+			if err != nil {
+				return nil, err
+			}
+
+			item.Error = errorMessage.String
+			job.Items = append(job.Items, item)
+		}
+
+		err = rows.Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return job, nil
+}
+
+// GetJobs returns the list of the latest jobs without their items.
+func (q *JobQueue) GetJobs(limit int) ([]Job, error) {
+	rows, err := q.db.Query("SELECT id, action, scope, status, created_at, started_at, finished_at FROM jobs ORDER BY id DESC LIMIT ?;", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, *job)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		err = q.loadCounts(&jobs[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+// RegisterAction registers a new action for jobs.
+func (q *JobQueue) RegisterAction(name string, action JobActionFunc) {
+	q.actions[strings.TrimSpace(strings.ToLower(name))] = action
+}
+
+// Start opens the database, resets items that have been interrupted
+// by a restart and starts the workers.
+func (q *JobQueue) Start() error {
+	db, err := q.app.OpenImageDatabase()
+	if err != nil {
+		return err
+	}
+
+	// items that were running while the backend stopped
+	_, err = db.Exec("UPDATE job_items SET status = ? WHERE status = ?;", JobStatusPending, JobStatusRunning)
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	q.db = db
+
+	for i := 0; i < q.workers; i++ {
+		go q.work()
+	}
+
+	return nil
+}
+
+func (q *JobQueue) claimNextItem() (int64, int64, string, bool, error) {
+	row := q.db.QueryRow(`UPDATE job_items SET status = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = (SELECT id FROM job_items WHERE status = ? ORDER BY id LIMIT 1)
+RETURNING id, job_id, file_path;`, JobStatusRunning, JobStatusPending)
+
+	var itemId, jobId int64
+	var filePath string
+	err := row.Scan(&itemId, &jobId, &filePath)
+	if err == sql.ErrNoRows {
+		return 0, 0, "", false, nil
+	}
+	if err != nil {
+		return 0, 0, "", false, err
+	}
+
+	return itemId, jobId, filePath, true, nil
+}
+
+func (q *JobQueue) finishItem(itemId int64, jobId int64, itemErr error) error {
+	status := JobStatusDone
+	var errorMessage any
+	if itemErr != nil {
+		status = JobStatusFailed
+		errorMessage = itemErr.Error()
+	}
+
+	_, err := q.db.Exec("UPDATE job_items SET status = ?, error = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;", status, errorMessage, itemId)
+	if err != nil {
+		return err
+	}
+
+	// a job fails, if none of its items could be processed
+	_, err = q.db.Exec(`UPDATE jobs SET finished_at = CURRENT_TIMESTAMP,
+  status = CASE WHEN EXISTS (SELECT 1 FROM job_items WHERE job_id = jobs.id AND status = ?) THEN ? ELSE ? END
+WHERE id = ? AND NOT EXISTS (SELECT 1 FROM job_items WHERE job_id = ? AND status IN (?, ?));`,
+		JobStatusDone, JobStatusDone, JobStatusFailed, jobId, jobId, JobStatusPending, JobStatusRunning,
+	)
+	return err
+}
+
+func (q *JobQueue) loadCounts(job *Job) error {
+	rows, err := q.db.Query("SELECT status, COUNT(*) FROM job_items WHERE job_id = ? GROUP BY status;", job.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	job.Counts = JobCounts{}
+	for rows.Next() {
+		var status JobStatus
+		var count int
+		err = rows.Scan(&status, &count)
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case JobStatusDone:
+			job.Counts.Done = count
+		case JobStatusFailed:
+			job.Counts.Failed = count
+		case JobStatusPending:
+			job.Counts.Pending = count
+		case JobStatusRunning:
+			job.Counts.Running = count
+		}
+
+		job.Counts.Total += count
+	}
+
+	return rows.Err()
+}
+
+func (q *JobQueue) processItem(itemId int64, jobId int64, filePath string) error {
+	_, err := q.db.Exec("UPDATE jobs SET status = ?, started_at = COALESCE(started_at, CURRENT_TIMESTAMP) WHERE id = ? AND status = ?;", JobStatusRunning, jobId, JobStatusPending)
+	if err != nil {
+		return err
+	}
+
+	var action string
+	err = q.db.QueryRow("SELECT action FROM jobs WHERE id = ?;", jobId).Scan(&action)
+	if err != nil {
+		return err
+	}
+
+	var itemErr error
+	actionFunc, ok := q.actions[action]
+	if ok {
+		fmt.Fprintf(q.app.Stdout, "Job %d: running '%s' for file '%s' ...%s", jobId, action, filePath, q.app.EOL)
+
+		itemErr = actionFunc(q.app, q.db, filePath)
+	} else {
+		itemErr = fmt.Errorf("job action '%s' is not supported", action)
+	}
+
+	if itemErr != nil {
+		fmt.Fprintf(q.app.Stderr, "Job %d: '%s' failed for file '%s': %s%s", jobId, action, filePath, itemErr.Error(), q.app.EOL)
+	}
+
+	return q.finishItem(itemId, jobId, itemErr)
+}
+
+func (q *JobQueue) resolveScope(scope string, names []string) ([]string, error) {
+	fileNames := make([]string, 0)
+
+	if scope == JobScopeNames {
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			name, err := CleanImageName(name)
+			if err != nil {
+				return nil, err
+			}
+			fileNames = append(fileNames, name)
+		}
+
+		return fileNames, nil
+	}
+
+	if scope != JobScopeAll && scope != JobScopeStale && scope != JobScopeUntagged {
+		return nil, fmt.Errorf("%w: scope '%s' is not supported", ErrInvalidJob, scope)
+	}
+
+	query := &ImageListQuery{
+		Recursive: true,
+	}
+	if scope == JobScopeStale {
+		query.Statuses = []ImageStatus{ImageStatusStale}
+	} else if scope == JobScopeUntagged {
+		query.Statuses = []ImageStatus{ImageStatusUntagged}
+	}
+
+	images, err := q.app.ListImages(q.db, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, image := range images.Images {
+		fileNames = append(fileNames, image.Name)
+	}
+
+	return fileNames, nil
+}
+
+func (q *JobQueue) wakeUp() {
+	for i := 0; i < q.workers; i++ {
+		select {
+		case q.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (q *JobQueue) work() {
+	for {
+		itemId, jobId, filePath, ok, err := q.claimNextItem()
+		if err != nil {
+			fmt.Fprintf(q.app.Stderr, "[JOB ERROR]: %s%s", err.Error(), q.app.EOL)
+		}
+
+		if !ok {
+			select {
+			case <-q.notify:
+			case <-time.After(30 * time.Second):
+			}
+
+			continue
+		}
+
+		err = q.processItem(itemId, jobId, filePath)
+		if err != nil {
+			fmt.Fprintf(q.app.Stderr, "[JOB ERROR]: %s%s", err.Error(), q.app.EOL)
+		}
+	}
+}
+
+type jobScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row jobScanner) (*Job, error) {
+	var job Job
+	var createdAt, startedAt, finishedAt sql.NullTime
+	err := row.Scan(&job.Id, &job.Action, &job.Scope, &job.Status, &createdAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if createdAt.Valid {
+		job.CreatedAt = &createdAt.Time
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build sqlite_fts5
+
+package types_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+func TestJobQueue(t *testing.T) {
+	app, _ := newTestApp(t, &tagsVisionProvider{})
+
+	queue := types.NewJobQueue(app, 1)
+	queue.RegisterAction("check", func(app *types.AppContext, db *sql.DB, filePath string) error {
+		if filePath != "ok.jpg" {
+			return fmt.Errorf("'%s' is not ok", filePath)
+		}
+		return nil
+	})
+
+	err := queue.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		action         string
+		scope          string
+		names          []string
+		expectedError  error
+		expectedStatus types.JobStatus
+	}{
+		{name: "all items done", action: "check", names: []string{"ok.jpg"}, expectedStatus: types.JobStatusDone},
+		{name: "some items failed", action: "check", names: []string{"ok.jpg", "bad.jpg"}, expectedStatus: types.JobStatusDone},
+		{name: "all items failed", action: "check", names: []string{"bad.jpg", "worse.jpg"}, expectedStatus: types.JobStatusFailed},
+		{name: "no items", action: "check", names: []string{}, expectedStatus: types.JobStatusDone},
+		{name: "unknown action", action: "paint", names: []string{"ok.jpg"}, expectedError: types.ErrInvalidJob},
+		{name: "unknown scope", action: "check", scope: "some", expectedError: types.ErrInvalidJob},
+		{name: "invalid name", action: "check", names: []string{"../ok.jpg"}, expectedError: types.ErrInvalidImagePath},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job, err := queue.CreateJob(test.action, test.scope, test.names)
+			if test.expectedError != nil {
+				if !errors.Is(err, test.expectedError) {
+					t.Errorf("got error %v, expected %v", err, test.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deadline := time.Now().Add(10 * time.Second)
+			for job.Status == types.JobStatusPending || job.Status == types.JobStatusRunning {
+				if time.Now().After(deadline) {
+					t.Fatalf("job %d has not finished", job.Id)
+				}
+				time.Sleep(10 * time.Millisecond)
+
+				job, err = queue.GetJob(job.Id, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if job.Status != test.expectedStatus {
+				t.Errorf("got status %q, expected %q", job.Status, test.expectedStatus)
+			}
+			if job.Counts.Total != len(test.names) {
+				t.Errorf("got %d items, expected %d", job.Counts.Total, len(test.names))
+			}
+		})
+	}
+}
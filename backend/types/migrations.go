@@ -0,0 +1,258 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is an up-migration of the image database.
+type Migration struct {
+	// Name stores the name of the migration.
+	Name string
+	// Sql stores the SQL statements.
+	Sql string
+	// Version stores the schema version after the migration.
+	Version int
+}
+
+// GetMigrations returns the list of all migrations, ordered by version.
+// Files are named like `0001_name.sql`.
+func GetMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0)
+	for _, entry := range entries {
+		versionPart, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name '%s'", entry.Name())
+		}
+
+		version, err := strconv.Atoi(versionPart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version in migration file name '%s'", entry.Name())
+		}
+
+		data, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Name:    name,
+			Sql:     string(data),
+			Version: version,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration version %d is missing", i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+// GetPendingMigrations returns the list of migrations, which have
+// not been applied to the image database yet.
+// Returns an error if the database has a newer schema than this backend.
+func (app *AppContext) GetPendingMigrations(db *sql.DB) ([]Migration, error) {
+	migrations, err := GetMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	currentVersion, err := getSchemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+
+	latestVersion := len(migrations)
+	if currentVersion > latestVersion {
+		return nil, fmt.Errorf(
+			"database schema version %d is newer than the latest known version %d, please update the backend",
+			currentVersion, latestVersion,
+		)
+	}
+
+	return migrations[currentVersion:], nil
+}
+
+// MigrateImageDatabase applies all pending migrations to the image database.
+func (app *AppContext) MigrateImageDatabase() error {
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+  version INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  applied_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);`)
+	if err != nil {
+		return err
+	}
+
+	pendingMigrations, err := app.GetPendingMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pendingMigrations {
+		fmt.Fprintf(app.Stdout, "Applying database migration %04d_%s ...%s", m.Version, m.Name, app.EOL)
+
+		err = applyMigration(db, m)
+		if err != nil {
+			return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var legacyTags map[int64]string
+	if m.Version == 1 {
+		legacyTags, err = dropLegacyTags(tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(m.Sql)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			return fmt.Errorf("SQLite has no FTS5 support, build with '-tags sqlite_fts5': %w", err)
+		}
+		return err
+	}
+
+	for imageId, tags := range legacyTags {
+		err = SetImageTags(tx, imageId, strings.Split(tags, ","))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO schema_version (version, name) VALUES (?, ?);", m.Version, m.Name)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// dropLegacyTags removes the comma separated `images.tags` column
+// of databases from before schema versioning, together with the search
+// index depending on it, and returns the old values by image ID.
+func dropLegacyTags(tx *sql.Tx) (map[int64]string, error) {
+	hasLegacyTags, err := hasColumn(tx, "images", "tags")
+	if err != nil || !hasLegacyTags {
+		return nil, err
+	}
+
+	rows, err := tx.Query("SELECT id, tags FROM images;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legacyTags := map[int64]string{}
+	for rows.Next() {
+		var imageId int64
+		var tags string
+		err = rows.Scan(&imageId, &tags)
+		if err != nil {
+			return nil, err
+		}
+
+		legacyTags[imageId] = tags
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	statements := []string{
+		"DROP TRIGGER IF EXISTS images_fts_after_insert;",
+		"DROP TRIGGER IF EXISTS images_fts_after_update;",
+		"DROP TRIGGER IF EXISTS images_fts_after_delete;",
+		"DROP TABLE IF EXISTS images_fts;",
+		"ALTER TABLE images DROP COLUMN tags;",
+	}
+	for _, stmt := range statements {
+		_, err = tx.Exec(stmt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return legacyTags, nil
+}
+
+func getSchemaVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	err := db.QueryRow("SELECT MAX(version) FROM schema_version;").Scan(&version)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such table") {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return int(version.Int64), nil
+}
+
+func hasColumn(tx *sql.Tx, table string, column string) (bool, error) {
+	var count int
+	err := tx.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?;", table, column).Scan(&count)
+
+	return count > 0, err
+}
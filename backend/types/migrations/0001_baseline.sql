@@ -0,0 +1,90 @@
+-- baseline schema, which also adopts databases
+-- that have been created before schema versioning
+
+CREATE TABLE IF NOT EXISTS images (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  file_path TEXT NOT NULL,
+  last_filesize INTEGER NOT NULL,
+  last_modified DATETIME NOT NULL,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_images_file_path ON images(file_path);
+
+CREATE TABLE IF NOT EXISTS jobs (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  action TEXT NOT NULL,
+  scope TEXT NOT NULL,
+  status TEXT NOT NULL,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  started_at DATETIME,
+  finished_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS job_items (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  job_id INTEGER NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  file_path TEXT NOT NULL,
+  status TEXT NOT NULL,
+  error TEXT,
+  updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_items_status ON job_items(status, job_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS image_tags (
+  image_id INTEGER NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (image_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_tags_tag_id ON image_tags(tag_id);
+
+-- full-text search, which requires `sqlite_fts5` build tag
+CREATE VIRTUAL TABLE IF NOT EXISTS images_fts USING fts5(
+  file_path, title, description, tags,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS images_fts_after_insert AFTER INSERT ON images BEGIN
+  INSERT INTO images_fts (rowid, file_path, title, description, tags)
+  VALUES (new.id, new.file_path, new.title, new.description, (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = new.id));
+END;
+
+CREATE TRIGGER IF NOT EXISTS images_fts_after_update AFTER UPDATE ON images BEGIN
+  DELETE FROM images_fts WHERE rowid = old.id;
+  INSERT INTO images_fts (rowid, file_path, title, description, tags)
+  VALUES (new.id, new.file_path, new.title, new.description, (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = new.id));
+END;
+
+CREATE TRIGGER IF NOT EXISTS images_fts_after_delete AFTER DELETE ON images BEGIN
+  DELETE FROM images_fts WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS images_fts_after_tag_insert AFTER INSERT ON image_tags BEGIN
+  UPDATE images_fts SET tags = (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = new.image_id)
+  WHERE rowid = new.image_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS images_fts_after_tag_delete AFTER DELETE ON image_tags BEGIN
+  UPDATE images_fts SET tags = (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = old.image_id)
+  WHERE rowid = old.image_id;
+END;
+
+INSERT INTO images_fts (rowid, file_path, title, description, tags)
+SELECT id, file_path, title, description, (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = images.id)
+FROM images
+WHERE id NOT IN (SELECT rowid FROM images_fts);
@@ -0,0 +1,14 @@
+-- resumable uploads
+
+CREATE TABLE uploads (
+  id TEXT PRIMARY KEY,
+  filename TEXT NOT NULL,
+  folder TEXT NOT NULL,
+  upload_length INTEGER NOT NULL,
+  upload_offset INTEGER DEFAULT 0 NOT NULL,
+  tag INTEGER DEFAULT 0 NOT NULL,
+  file_path TEXT,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  updated_at DATETIME,
+  finished_at DATETIME
+);
@@ -0,0 +1,7 @@
+-- marks who wrote title, description and tags: 'ai' or 'manual'
+
+ALTER TABLE images ADD COLUMN title_source TEXT;
+ALTER TABLE images ADD COLUMN description_source TEXT;
+ALTER TABLE images ADD COLUMN tags_source TEXT;
+
+UPDATE images SET title_source = 'ai', description_source = 'ai', tags_source = 'ai';
@@ -0,0 +1,6 @@
+-- per-field locks, which protect title, description and tags
+-- from being overwritten by the vision provider
+
+ALTER TABLE images ADD COLUMN title_locked INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE images ADD COLUMN description_locked INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE images ADD COLUMN tags_locked INTEGER DEFAULT 0 NOT NULL;
@@ -0,0 +1,22 @@
+-- EXIF and XMP metadata, which is extracted from the image files
+
+CREATE TABLE image_metadata (
+  file_path TEXT PRIMARY KEY,
+  indexed_filesize INTEGER NOT NULL,
+  indexed_modified DATETIME NOT NULL,
+  taken_at TEXT,
+  camera_make TEXT,
+  camera_model TEXT,
+  lens_model TEXT,
+  exposure_time REAL,
+  f_number REAL,
+  focal_length REAL,
+  iso INTEGER,
+  orientation INTEGER,
+  gps_latitude REAL,
+  gps_longitude REAL,
+  gps_altitude REAL,
+  indexed_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_image_metadata_taken_at ON image_metadata(taken_at);
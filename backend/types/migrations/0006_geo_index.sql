@@ -0,0 +1,3 @@
+-- speeds up bounding box queries
+
+CREATE INDEX idx_image_metadata_gps ON image_metadata(gps_latitude, gps_longitude);
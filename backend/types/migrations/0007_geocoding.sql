@@ -0,0 +1,72 @@
+-- place names from reverse geocoding, which are also searchable
+
+ALTER TABLE image_metadata ADD COLUMN country_code TEXT;
+ALTER TABLE image_metadata ADD COLUMN country TEXT;
+ALTER TABLE image_metadata ADD COLUMN region TEXT;
+ALTER TABLE image_metadata ADD COLUMN city TEXT;
+
+DROP TRIGGER IF EXISTS images_fts_after_insert;
+DROP TRIGGER IF EXISTS images_fts_after_update;
+DROP TRIGGER IF EXISTS images_fts_after_delete;
+DROP TRIGGER IF EXISTS images_fts_after_tag_insert;
+DROP TRIGGER IF EXISTS images_fts_after_tag_delete;
+DROP TABLE IF EXISTS images_fts;
+
+CREATE VIRTUAL TABLE images_fts USING fts5(
+  file_path, title, description, tags, location,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER images_fts_after_insert AFTER INSERT ON images BEGIN
+  INSERT INTO images_fts (rowid, file_path, title, description, tags, location)
+  VALUES (new.id, new.file_path, new.title, new.description, (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = new.id),
+    (SELECT concat_ws(', ', m.city, m.region, m.country) FROM image_metadata m WHERE m.file_path = new.file_path));
+END;
+
+CREATE TRIGGER images_fts_after_update AFTER UPDATE ON images BEGIN
+  DELETE FROM images_fts WHERE rowid = old.id;
+  INSERT INTO images_fts (rowid, file_path, title, description, tags, location)
+  VALUES (new.id, new.file_path, new.title, new.description, (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = new.id),
+    (SELECT concat_ws(', ', m.city, m.region, m.country) FROM image_metadata m WHERE m.file_path = new.file_path));
+END;
+
+CREATE TRIGGER images_fts_after_delete AFTER DELETE ON images BEGIN
+  DELETE FROM images_fts WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER images_fts_after_tag_insert AFTER INSERT ON image_tags BEGIN
+  UPDATE images_fts SET tags = (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = new.image_id)
+  WHERE rowid = new.image_id;
+END;
+
+CREATE TRIGGER images_fts_after_tag_delete AFTER DELETE ON image_tags BEGIN
+  UPDATE images_fts SET tags = (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = old.image_id)
+  WHERE rowid = old.image_id;
+END;
+
+CREATE TRIGGER images_fts_after_location_insert AFTER INSERT ON image_metadata BEGIN
+  UPDATE images_fts SET location = concat_ws(', ', new.city, new.region, new.country)
+  WHERE rowid = (SELECT id FROM images WHERE file_path = new.file_path);
+END;
+
+CREATE TRIGGER images_fts_after_location_update AFTER UPDATE ON image_metadata BEGIN
+  UPDATE images_fts SET location = NULL
+  WHERE rowid = (SELECT id FROM images WHERE file_path = old.file_path);
+  UPDATE images_fts SET location = concat_ws(', ', new.city, new.region, new.country)
+  WHERE rowid = (SELECT id FROM images WHERE file_path = new.file_path);
+END;
+
+CREATE TRIGGER images_fts_after_location_delete AFTER DELETE ON image_metadata BEGIN
+  UPDATE images_fts SET location = NULL
+  WHERE rowid = (SELECT id FROM images WHERE file_path = old.file_path);
+END;
+
+INSERT INTO images_fts (rowid, file_path, title, description, tags, location)
+SELECT id, file_path, title, description, (SELECT group_concat(t.name, ', ') FROM image_tags it
+    INNER JOIN tags t ON t.id = it.tag_id WHERE it.image_id = images.id),
+    (SELECT concat_ws(', ', m.city, m.region, m.country) FROM image_metadata m WHERE m.file_path = images.file_path)
+FROM images;
@@ -0,0 +1,8 @@
+-- perceptual hashes for duplicate detection and the preferred copy
+-- of duplicates
+
+ALTER TABLE image_metadata ADD COLUMN dhash TEXT;
+ALTER TABLE image_metadata ADD COLUMN preferred INTEGER DEFAULT 0 NOT NULL;
+
+-- index all files again, so they get a hash
+UPDATE image_metadata SET indexed_filesize = -1;
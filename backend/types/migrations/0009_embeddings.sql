@@ -0,0 +1,12 @@
+-- embedding vectors for semantic search as little endian float32 values;
+-- source_hash is the SHA-256 of the embedded text or image
+
+CREATE TABLE image_embeddings (
+  file_path TEXT NOT NULL,
+  kind TEXT NOT NULL,
+  model TEXT NOT NULL,
+  source_hash TEXT NOT NULL,
+  vector BLOB NOT NULL,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  PRIMARY KEY (file_path, kind)
+);
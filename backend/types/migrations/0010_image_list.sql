@@ -0,0 +1,3 @@
+-- index for sorting and filtering the image list by date
+
+CREATE INDEX idx_image_metadata_taken_at_file_path ON image_metadata(taken_at, file_path);
@@ -0,0 +1,35 @@
+-- inventory of the files in the image folder, so lists are read
+-- from the database instead of the file system
+
+CREATE TABLE files (
+  file_path TEXT PRIMARY KEY,
+  filesize INTEGER NOT NULL,
+  modified DATETIME NOT NULL,
+  mime_type TEXT NOT NULL,
+  width INTEGER,
+  height INTEGER,
+  content_hash TEXT,
+  indexed_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_files_content_hash ON files(content_hash);
+CREATE INDEX idx_files_filesize ON files(filesize, file_path);
+CREATE INDEX idx_files_mime_type ON files(mime_type);
+
+CREATE VIEW image_files AS SELECT * FROM files WHERE mime_type LIKE 'image/%';
+
+-- known files are listed until the first scan, which detects
+-- the MIME type by content and adds dimensions and hashes
+INSERT INTO files (file_path, filesize, modified, mime_type)
+SELECT file_path, indexed_filesize, indexed_modified, mime_type FROM (
+  SELECT file_path, indexed_filesize, indexed_modified,
+    CASE
+      WHEN lower(file_path) LIKE '%.jpg' OR lower(file_path) LIKE '%.jpeg' THEN 'image/jpeg'
+      WHEN lower(file_path) LIKE '%.png' THEN 'image/png'
+      WHEN lower(file_path) LIKE '%.gif' THEN 'image/gif'
+      WHEN lower(file_path) LIKE '%.webp' THEN 'image/webp'
+    END AS mime_type
+  FROM image_metadata
+  WHERE indexed_filesize >= 0
+)
+WHERE mime_type IS NOT NULL;
@@ -0,0 +1,7 @@
+-- files, which have been removed from the image folder, are marked as
+-- deleted for a while, so moved files can be found by their hash
+
+ALTER TABLE files ADD COLUMN deleted_at DATETIME;
+
+DROP VIEW image_files;
+CREATE VIEW image_files AS SELECT * FROM files WHERE mime_type LIKE 'image/%' AND deleted_at IS NULL;
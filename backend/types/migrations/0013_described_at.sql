@@ -0,0 +1,12 @@
+-- stores when title, description or tags have been written, so images,
+-- whose fields have only been locked so far, are untagged
+
+ALTER TABLE images ADD COLUMN described_at DATETIME;
+
+UPDATE images SET described_at = COALESCE(updated_at, CURRENT_TIMESTAMP) WHERE last_filesize <> -1;
+
+-- locks have stored an invalid file size before
+UPDATE images SET
+  last_filesize = (SELECT f.filesize FROM files f WHERE f.file_path = images.file_path),
+  last_modified = (SELECT f.modified FROM files f WHERE f.file_path = images.file_path)
+WHERE last_filesize = -1 AND file_path IN (SELECT file_path FROM files);
@@ -29,3 +29,11 @@ type OllamaApiCompletionResponse struct {
 	// Response stores the messagefrom assistant.
 	Response string `json:"response,omitempty"`
 }
+
+// OllamaApiEmbedResponse is the data of a successful embed response.
+type OllamaApiEmbedResponse struct {
+	// Embeddings stores one vector for each input.
+	Embeddings [][]float32 `json:"embeddings"`
+	// Model stores the model that has been used.
+	Model string `json:"model,omitempty"`
+}
@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+// OpenAIChatCompletionResponse is the data of a successful chat completion response.
+type OpenAIChatCompletionResponse struct {
+	// Choices stores the list of choices.
+	Choices []OpenAIChatCompletionResponseChoice `json:"choices,omitempty"`
+	// Model stores the model that has been used.
+	Model string `json:"model,omitempty"`
+}
+
+// OpenAIChatCompletionResponseChoice is an item of Choices property of OpenAIChatCompletionResponse.
+type OpenAIChatCompletionResponseChoice struct {
+	// Message stores the message from assistant.
+	Message OpenAIChatCompletionResponseMessage `json:"message"`
+}
+
+// OpenAIChatCompletionResponseMessage is the Message property of OpenAIChatCompletionResponseChoice.
+type OpenAIChatCompletionResponseMessage struct {
+	// Content stores the content of the message.
+	Content string `json:"content,omitempty"`
+	// Role stores the role of the sender.
+	Role string `json:"role,omitempty"`
+}
+
+// OpenAIEmbeddingsResponse is the data of a successful embeddings response.
+type OpenAIEmbeddingsResponse struct {
+	// Data stores one item for each input.
+	Data []OpenAIEmbeddingsResponseData `json:"data"`
+	// Model stores the model that has been used.
+	Model string `json:"model,omitempty"`
+}
+
+// OpenAIEmbeddingsResponseData is an item of Data property of OpenAIEmbeddingsResponse.
+type OpenAIEmbeddingsResponseData struct {
+	// Embedding stores the vector.
+	Embedding []float32 `json:"embedding"`
+	// Index stores the index of the input.
+	Index int `json:"index"`
+}
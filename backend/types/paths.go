@@ -0,0 +1,141 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+var (
+	// ErrImageNotFound is returned if an image path does not exist.
+	ErrImageNotFound = errors.New("image not found")
+	// ErrInvalidImagePath is returned if an image path is malformed
+	// or leaves the image folder.
+	ErrInvalidImagePath = errors.New("invalid image path")
+)
+
+// CleanImageName normalizes the relative path of an image
+// and checks that it does not leave the image folder.
+func CleanImageName(imageName string) (string, error) {
+	name := strings.ReplaceAll(imageName, "\\", "/")
+	if strings.ContainsRune(name, 0) || slices.Contains(strings.Split(name, "/"), "..") {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidImagePath, imageName)
+	}
+
+	name = path.Clean("/" + name)
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidImagePath, imageName)
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		// hidden files and folders, like the thumbnail cache
+		if strings.HasPrefix(segment, ".") {
+			return "", fmt.Errorf("%w: '%s'", ErrInvalidImagePath, imageName)
+		}
+	}
+
+	return name, nil
+}
+
+// GetImagePath returns the full path of an image by its relative path
+// inside the image folder, without checking the file system.
+// Use ResolveImagePath() for existing files.
+func (app *AppContext) GetImagePath(imageName string) (string, error) {
+	relPath, err := CleanImageName(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(app.GetImageFolder(), filepath.FromSlash(relPath)), nil
+}
+
+// ResolveImagePath resolves the relative path of an existing file or folder
+// inside the image folder, including symbolic links, and returns the
+// normalized relative path and the full path of the target.
+//
+// Returns an error wrapping ErrInvalidImagePath if the path is malformed
+// or its target is outside the image folder, and ErrImageNotFound
+// if it does not exist.
+func (app *AppContext) ResolveImagePath(imageName string) (string, string, error) {
+	relPath, err := CleanImageName(imageName)
+	if err != nil {
+		return "", "", err
+	}
+
+	fullPath := filepath.Join(app.GetImageFolder(), filepath.FromSlash(relPath))
+	if app.isDatabaseFile(fullPath) {
+		return "", "", fmt.Errorf("%w: '%s'", ErrImageNotFound, imageName)
+	}
+
+	rootPath, err := filepath.EvalSymlinks(app.GetImageFolder())
+	if err != nil {
+		return "", "", err
+	}
+
+	resolvedPath, err := filepath.EvalSymlinks(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) || errors.Is(err, syscall.ENOTDIR) {
+			return "", "", fmt.Errorf("%w: '%s'", ErrImageNotFound, imageName)
+		}
+		return "", "", err
+	}
+
+	rel, err := filepath.Rel(rootPath, resolvedPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", fmt.Errorf("%w: '%s' points outside of image folder", ErrInvalidImagePath, imageName)
+	}
+
+	if _, err := CleanImageName(filepath.ToSlash(rel)); err != nil && rel != "." {
+		return "", "", fmt.Errorf("%w: '%s' points to a hidden file", ErrInvalidImagePath, imageName)
+	}
+
+	if app.isDatabaseFile(resolvedPath) {
+		return "", "", fmt.Errorf("%w: '%s'", ErrImageNotFound, imageName)
+	}
+
+	return relPath, resolvedPath, nil
+}
+
+func (app *AppContext) isDatabaseFile(fullPath string) bool {
+	databaseFile := filepath.Clean(app.Config.DatabaseFile)
+	if resolved, err := filepath.EvalSymlinks(databaseFile); err == nil {
+		databaseFile = resolved
+	}
+
+	fullPath = filepath.Clean(fullPath)
+	for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+		if fullPath == databaseFile+suffix || fullPath == filepath.Clean(app.Config.DatabaseFile)+suffix {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestAppContext(t *testing.T) *AppContext {
+	t.Helper()
+
+	imageFolder := t.TempDir()
+
+	return &AppContext{
+		Config: &AppConfig{
+			DatabaseFile: filepath.Join(imageFolder, "images.db"),
+			ImageFolder:  imageFolder,
+		},
+		EOL:    "\n",
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+func writeTestFile(t *testing.T, fullPath string, data []byte) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(fullPath), 0o755)
+	if err == nil {
+		err = os.WriteFile(fullPath, data, 0o644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCleanImageName(t *testing.T) {
+	valid := map[string]string{
+		"photo.png":            "photo.png",
+		"2024/summer/a.jpg":    "2024/summer/a.jpg",
+		"/2024/a.jpg":          "2024/a.jpg",
+		"2024//a.jpg":          "2024/a.jpg",
+		"2024\\a.jpg":          "2024/a.jpg",
+		"%2e%2e/a.jpg":         "%2e%2e/a.jpg",
+		"./2024/a.jpg":         "2024/a.jpg",
+		"name with spaces.png": "name with spaces.png",
+	}
+	for input, expected := range valid {
+		name, err := CleanImageName(input)
+		if err != nil {
+			t.Errorf("CleanImageName(%q) failed: %v", input, err)
+		} else if name != expected {
+			t.Errorf("CleanImageName(%q) = %q, expected %q", input, name, expected)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"/",
+		"..",
+		"../a.jpg",
+		"2024/../../a.jpg",
+		"..\\a.jpg",
+		"2024\\..\\..\\a.jpg",
+		"a.jpg\x00.png",
+		".maig/thumbnails/a.jpg",
+		"2024/.hidden.jpg",
+		".env",
+	}
+	for _, input := range invalid {
+		_, err := CleanImageName(input)
+		if !errors.Is(err, ErrInvalidImagePath) {
+			t.Errorf("CleanImageName(%q) returned %v, expected ErrInvalidImagePath", input, err)
+		}
+	}
+}
+
+func TestResolveImagePath(t *testing.T) {
+	app := newTestAppContext(t)
+	imageFolder := app.GetImageFolder()
+	outsideFolder := t.TempDir()
+
+	writeTestFile(t, filepath.Join(imageFolder, "photo.png"), []byte("png"))
+	writeTestFile(t, filepath.Join(imageFolder, "2024", "a.jpg"), []byte("jpg"))
+	writeTestFile(t, filepath.Join(imageFolder, ".maig", "thumbnails", "a.jpg"), []byte("jpg"))
+	writeTestFile(t, filepath.Join(outsideFolder, "secret.png"), []byte("secret"))
+	for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+		writeTestFile(t, app.Config.DatabaseFile+suffix, []byte("db"))
+	}
+
+	symlinks := map[string]string{
+		"escape":       outsideFolder,
+		"escape.png":   filepath.Join(outsideFolder, "secret.png"),
+		"hidden":       filepath.Join(imageFolder, ".maig"),
+		"database.png": app.Config.DatabaseFile,
+		"inside.png":   filepath.Join(imageFolder, "photo.png"),
+	}
+	for name, target := range symlinks {
+		err := os.Symlink(target, filepath.Join(imageFolder, name))
+		if err != nil {
+			t.Skipf("symbolic links are not supported: %v", err)
+		}
+	}
+
+	valid := map[string]string{
+		"photo.png":  "photo.png",
+		"2024/a.jpg": "2024/a.jpg",
+		"2024":       "2024",
+		"inside.png": "inside.png",
+	}
+	for input, expected := range valid {
+		name, _, err := app.ResolveImagePath(input)
+		if err != nil {
+			t.Errorf("ResolveImagePath(%q) failed: %v", input, err)
+		} else if name != expected {
+			t.Errorf("ResolveImagePath(%q) = %q, expected %q", input, name, expected)
+		}
+	}
+
+	hostile := []string{
+		"../photo.png",
+		"2024/../../photo.png",
+		"..\\" + filepath.Base(outsideFolder) + "\\secret.png",
+		"2024\\..\\..\\photo.png",
+		"%2e%2e/%2e%2e/etc/passwd",
+		"%2e%2e%2fphoto.png",
+		"photo.png\x00.jpg",
+		".maig/thumbnails/a.jpg",
+		"hidden/thumbnails/a.jpg",
+		"/etc/passwd",
+		filepath.Join(outsideFolder, "secret.png"),
+		"escape/secret.png",
+		"escape.png",
+		"images.db",
+		"images.db-wal",
+		"images.db-shm",
+		"images.db-journal",
+		"database.png",
+		"missing.png",
+		"photo.png/a.jpg",
+	}
+	for _, input := range hostile {
+		_, fullPath, err := app.ResolveImagePath(input)
+		if !errors.Is(err, ErrInvalidImagePath) && !errors.Is(err, ErrImageNotFound) {
+			t.Errorf("ResolveImagePath(%q) returned '%s' and %v, expected ErrInvalidImagePath or ErrImageNotFound", input, fullPath, err)
+		}
+	}
+}
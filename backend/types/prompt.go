@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mkloubert/my-ai-gallery/metadata"
+)
+
+// DefaultPromptContextTemplate is the default template for the context
+// block, which is added to the prompt, if enabled.
+const DefaultPromptContextTemplate = `{{if or .TakenAt .Place .Position .Camera -}}
+Use the following information about the image for title and description, if it fits:
+{{- if .TakenAt}}
+- taken on {{.TakenAt.Format "Monday, January 2, 2006 at 15:04"}}
+{{- end}}
+{{- if .Place}}
+- taken in {{.Place}}
+{{- else if .Position}}
+- taken at GPS position {{printf "%.5f" .Position.Latitude}}, {{printf "%.5f" .Position.Longitude}}
+{{- end}}
+{{- if .Camera}}
+- taken with {{.Camera}}{{if .Lens}} and {{.Lens}}{{end}}
+{{- end}}
+{{- end}}`
+
+// PromptContext stores the data for the prompt context template.
+type PromptContext struct {
+	// Camera stores make and model of the camera.
+	Camera string
+	// Filename stores the relative path of the image.
+	Filename string
+	// Lens stores the model of the lens.
+	Lens string
+	// Location stores the place of the GPS position, if known.
+	Location *ImageLocation
+	// Metadata stores all EXIF and XMP metadata.
+	Metadata *metadata.Metadata
+	// Place stores city, region and country of the GPS position, if known.
+	Place string
+	// Position stores the GPS position, if known.
+	Position *metadata.GPSPosition
+	// TakenAt stores the time, when the image has been taken, if known.
+	TakenAt *time.Time
+}
+
+// BuildVisionPrompt returns the prompt for an image, which contains
+// the context block with its metadata, if enabled.
+func (app *AppContext) BuildVisionPrompt(imageName string, meta *ImageMetadata) (string, error) {
+	prompt := app.Config.Vision.Prompt
+	if !app.Config.Vision.PromptContext || meta == nil {
+		return prompt, nil
+	}
+
+	tmpl, err := ParsePromptContextTemplate(app.Config.Vision.ContextTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	contextBlock, err := RenderPromptContext(tmpl, NewPromptContext(imageName, meta))
+	if err != nil {
+		return "", err
+	}
+	if contextBlock == "" {
+		return prompt, nil
+	}
+
+	return prompt + "\n\n" + contextBlock, nil
+}
+
+// NewPromptContext creates the data for the prompt context template.
+func NewPromptContext(imageName string, meta *ImageMetadata) *PromptContext {
+	ctx := &PromptContext{
+		Filename: imageName,
+		Lens:     meta.LensModel,
+		Location: meta.Location,
+		Metadata: &meta.Metadata,
+		Position: meta.GPS,
+	}
+	if meta.Location != nil {
+		ctx.Place = strings.Join(meta.Location.Names(), ", ")
+	}
+
+	// the model name often contains the make already
+	ctx.Camera = meta.CameraModel
+	if meta.CameraMake != "" && !strings.HasPrefix(strings.ToLower(meta.CameraModel), strings.ToLower(meta.CameraMake)) {
+		ctx.Camera = strings.TrimSpace(meta.CameraMake + " " + meta.CameraModel)
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05Z07:00", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, meta.TakenAt); err == nil {
+			ctx.TakenAt = &t
+			break
+		}
+	}
+
+	return ctx
+}
+
+// ParsePromptContextTemplate parses a template for the prompt context block.
+// An empty text uses DefaultPromptContextTemplate.
+func ParsePromptContextTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultPromptContextTemplate
+	}
+
+	tmpl, err := template.New("prompt_context").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt context template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// RenderPromptContext renders the prompt context block for ctx.
+func RenderPromptContext(tmpl *template.Template, ctx *PromptContext) (string, error) {
+	var result strings.Builder
+	err := tmpl.Execute(&result, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(result.String()), nil
+}
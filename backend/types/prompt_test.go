@@ -0,0 +1,208 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/mkloubert/my-ai-gallery/metadata"
+)
+
+const testCustomPromptContextTemplate = `File: {{.Filename}}
+{{- if .TakenAt}}
+Date: {{.TakenAt.Format "2006-01-02"}}
+{{- end}}
+{{- if .Place}}
+Place: {{.Place}}
+{{- else if .Position}}
+Position: {{printf "%.2f" .Position.Latitude}}/{{printf "%.2f" .Position.Longitude}}
+{{- end}}
+{{- if .Camera}}
+Camera: {{.Camera}}
+{{- end}}`
+
+func newTestImageMetadata(takenAt bool, position bool, location bool, camera bool) *ImageMetadata {
+	meta := &ImageMetadata{}
+	if takenAt {
+		meta.TakenAt = "2024-07-01T14:03:22+02:00"
+	}
+	if position {
+		meta.GPS = &metadata.GPSPosition{Latitude: 52.520094, Longitude: 13.404954}
+	}
+	if location {
+		meta.Location = &ImageLocation{City: "Berlin", Country: "Germany", CountryCode: "DE", Region: "Berlin"}
+	}
+	if camera {
+		meta.CameraMake = "Canon"
+		meta.CameraModel = "EOS R5"
+		meta.LensModel = "RF24-105mm F4 L IS USM"
+	}
+
+	return meta
+}
+
+func TestRenderPromptContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		meta     *ImageMetadata
+		expected string
+	}{
+		{
+			name:     "default without metadata",
+			meta:     newTestImageMetadata(false, false, false, false),
+			expected: "",
+		},
+		{
+			name: "default with capture date",
+			meta: newTestImageMetadata(true, false, false, false),
+			expected: "Use the following information about the image for title and description, if it fits:\n" +
+				"- taken on Monday, July 1, 2024 at 14:03",
+		},
+		{
+			name: "default with GPS position",
+			meta: newTestImageMetadata(false, true, false, false),
+			expected: "Use the following information about the image for title and description, if it fits:\n" +
+				"- taken at GPS position 52.52009, 13.40495",
+		},
+		{
+			name: "default with location",
+			meta: newTestImageMetadata(false, true, true, false),
+			expected: "Use the following information about the image for title and description, if it fits:\n" +
+				"- taken in Berlin, Germany",
+		},
+		{
+			name: "default with camera",
+			meta: newTestImageMetadata(false, false, false, true),
+			expected: "Use the following information about the image for title and description, if it fits:\n" +
+				"- taken with Canon EOS R5 and RF24-105mm F4 L IS USM",
+		},
+		{
+			name: "default with everything",
+			meta: newTestImageMetadata(true, true, true, true),
+			expected: "Use the following information about the image for title and description, if it fits:\n" +
+				"- taken on Monday, July 1, 2024 at 14:03\n" +
+				"- taken in Berlin, Germany\n" +
+				"- taken with Canon EOS R5 and RF24-105mm F4 L IS USM",
+		},
+		{
+			name:     "custom without metadata",
+			template: testCustomPromptContextTemplate,
+			meta:     newTestImageMetadata(false, false, false, false),
+			expected: "File: 2024/photo.jpg",
+		},
+		{
+			name:     "custom with capture date",
+			template: testCustomPromptContextTemplate,
+			meta:     newTestImageMetadata(true, false, false, false),
+			expected: "File: 2024/photo.jpg\nDate: 2024-07-01",
+		},
+		{
+			name:     "custom with GPS position",
+			template: testCustomPromptContextTemplate,
+			meta:     newTestImageMetadata(false, true, false, false),
+			expected: "File: 2024/photo.jpg\nPosition: 52.52/13.40",
+		},
+		{
+			name:     "custom with location",
+			template: testCustomPromptContextTemplate,
+			meta:     newTestImageMetadata(false, true, true, false),
+			expected: "File: 2024/photo.jpg\nPlace: Berlin, Germany",
+		},
+		{
+			name:     "custom with camera",
+			template: testCustomPromptContextTemplate,
+			meta:     newTestImageMetadata(false, false, false, true),
+			expected: "File: 2024/photo.jpg\nCamera: Canon EOS R5",
+		},
+		{
+			name:     "custom with everything",
+			template: testCustomPromptContextTemplate,
+			meta:     newTestImageMetadata(true, true, true, true),
+			expected: "File: 2024/photo.jpg\nDate: 2024-07-01\nPlace: Berlin, Germany\nCamera: Canon EOS R5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := ParsePromptContextTemplate(test.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			contextBlock, err := RenderPromptContext(tmpl, NewPromptContext("2024/photo.jpg", test.meta))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contextBlock != test.expected {
+				t.Errorf("got %q, expected %q", contextBlock, test.expected)
+			}
+		})
+	}
+}
+
+func TestBuildVisionPrompt(t *testing.T) {
+	app := newTestAppContext(t)
+	app.Config.Vision.Prompt = "What is in this image?"
+
+	meta := newTestImageMetadata(true, false, false, true)
+
+	// the context block is disabled
+	prompt, err := app.BuildVisionPrompt("photo.jpg", meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt != "What is in this image?" {
+		t.Errorf("got %q without context", prompt)
+	}
+
+	app.Config.Vision.PromptContext = true
+
+	prompt, err = app.BuildVisionPrompt("photo.jpg", meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "What is in this image?\n\n" +
+		"Use the following information about the image for title and description, if it fits:\n" +
+		"- taken on Monday, July 1, 2024 at 14:03\n" +
+		"- taken with Canon EOS R5 and RF24-105mm F4 L IS USM"
+	if prompt != expected {
+		t.Errorf("got %q, expected %q", prompt, expected)
+	}
+
+	// an empty context block keeps the prompt as it is
+	prompt, err = app.BuildVisionPrompt("photo.jpg", newTestImageMetadata(false, false, false, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt != "What is in this image?" {
+		t.Errorf("got %q without metadata", prompt)
+	}
+
+	app.Config.Vision.ContextTemplate = "{{.Unknown}}"
+
+	_, err = app.BuildVisionPrompt("photo.jpg", meta)
+	if err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
@@ -0,0 +1,144 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// markers of matches in FTS5 results, which are no HTML and
+// are replaced after the text has been escaped
+const (
+	searchMatchEnd   = "\x03"
+	searchMatchStart = "\x02"
+)
+
+// SearchResult stores the result of a SearchImages() call.
+type SearchResult struct {
+	// Images stores the list of matching images, ordered by rank.
+	Images []SearchResultImage `json:"images"`
+	// Total stores the total number of matching images.
+	Total int `json:"total"`
+}
+
+// SearchResultImage is an item of Images property of SearchResult.
+type SearchResultImage struct {
+	// Description stores the description of the image.
+	Description string `json:"description"`
+	// Name stores the relative path of the image.
+	Name string `json:"name"`
+	// Rank stores the BM25 rank. The lower the value, the better the match.
+	Rank float64 `json:"rank"`
+	// Snippet stores a part of the description as HTML, where matches are in <mark> elements.
+	Snippet string `json:"snippet"`
+	// Tags stores the list of tags.
+	Tags []string `json:"tags"`
+	// Title stores the title of the image.
+	Title string `json:"title"`
+	// TitleHighlighted stores the title as HTML, where matches are in <mark> elements.
+	TitleHighlighted string `json:"title_highlighted"`
+}
+
+// BuildSearchQuery converts user input to a FTS5 query, where
+// all words must match as prefix.
+func BuildSearchQuery(input string) string {
+	terms := make([]string, 0)
+
+	for _, word := range strings.Fields(input) {
+		// quoted strings are escaped by doubling quotes
+		word = strings.ReplaceAll(word, `"`, `""`)
+
+		terms = append(terms, fmt.Sprintf(`"%s"*`, word))
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// HighlightSearchMatches escapes a text with marked matches of a FTS5 result
+// as HTML and puts the matches into <mark> elements.
+func HighlightSearchMatches(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, searchMatchStart, "<mark>")
+	text = strings.ReplaceAll(text, searchMatchEnd, "</mark>")
+
+	return text
+}
+
+// SearchImages searches for images by title, description, tags and file path.
+func (app *AppContext) SearchImages(db *sql.DB, input string, limit int, offset int) (*SearchResult, error) {
+	result := &SearchResult{
+		Images: make([]SearchResultImage, 0),
+	}
+
+	query := BuildSearchQuery(input)
+	if query == "" {
+		return result, nil
+	}
+
+	// only images, which still exist in the inventory, like in `ListImages`
+	from := `FROM images_fts
+INNER JOIN images ON images.id = images_fts.rowid
+INNER JOIN image_files f ON f.file_path = images.file_path
+WHERE images_fts MATCH ?`
+
+	err := db.QueryRow("SELECT COUNT(*) "+from+";", query).Scan(&result.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT
+  images.file_path, images.title, images.description, `+TagsJSONColumn+`,
+  highlight(images_fts, 1, char(2), char(3)),
+  snippet(images_fts, 2, char(2), char(3), '...', 24),
+  bm25(images_fts, 2.0, 10.0, 4.0, 6.0, 6.0) AS rank
+`+from+`
+ORDER BY rank
+LIMIT ? OFFSET ?;`, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var image SearchResultImage
+		var tags sql.NullString
+		err = rows.Scan(
+			&image.Name, &image.Title, &image.Description, &tags,
+			&image.TitleHighlighted, &image.Snippet, &image.Rank,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		image.Snippet = HighlightSearchMatches(image.Snippet)
+		image.Tags = ParseTagsJSON(tags)
+		image.TitleHighlighted = HighlightSearchMatches(image.TitleHighlighted)
+
+		result.Images = append(result.Images, image)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,42 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import "testing"
+
+func TestHighlightSearchMatches(t *testing.T) {
+	tests := map[string]string{
+		"":                                      "",
+		"sunset at the beach":                   "sunset at the beach",
+		"\x02sunset\x03 at the \x02beach\x03":   "<mark>sunset</mark> at the <mark>beach</mark>",
+		"<script>alert(1)</script> \x02sun\x03": "&lt;script&gt;alert(1)&lt;/script&gt; <mark>sun</mark>",
+		"\x02<b>\x03 & \"quotes\"":              "<mark>&lt;b&gt;</mark> &amp; &#34;quotes&#34;",
+		"<mark>fake</mark>":                     "&lt;mark&gt;fake&lt;/mark&gt;",
+	}
+	for input, expected := range tests {
+		result := HighlightSearchMatches(input)
+		if result != expected {
+			t.Errorf("HighlightSearchMatches(%q) = %q, expected %q", input, result, expected)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package types
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mkloubert/my-ai-gallery/imagehash"
+	"github.com/mkloubert/my-ai-gallery/vectors"
+)
+
+const (
+	// SimilarByEmbedding compares the embeddings of images.
+	SimilarByEmbedding = "embedding"
+	// SimilarByHash compares the perceptual hashes of images.
+	SimilarByHash = "hash"
+)
+
+// ErrImageNotIndexed is returned, if an image has no embedding
+// or perceptual hash yet.
+var ErrImageNotIndexed = errors.New("image has not been indexed")
+
+// SimilarImage is an item of Images property of SimilarImagesResult.
+type SimilarImage struct {
+	// Distance stores the Hamming distance of the perceptual hashes, if compared by hash.
+	Distance *int `json:"distance,omitempty"`
+	// Name stores the relative path of the image.
+	Name string `json:"name"`
+	// Score stores the similarity between 0 and 1. The higher the value, the more similar.
+	Score float32 `json:"score"`
+	// Title stores the title of the image.
+	Title string `json:"title"`
+}
+
+// SimilarImagesResult stores the result of a FindSimilarImages() call.
+type SimilarImagesResult struct {
+	// By stores, if the images have been compared by `embedding` or `hash`.
+	By string `json:"by"`
+	// Images stores the list of images, the most similar first.
+	Images []SimilarImage `json:"images"`
+	// Name stores the relative path of the image, the others are similar to.
+	Name string `json:"name"`
+}
+
+// FindSimilarImages returns the images, which are the most similar to
+// an image, by their embeddings or perceptual hashes. If by is empty,
+// embeddings are used, if the image has one.
+func (app *AppContext) FindSimilarImages(db *sql.DB, imageName string, by string, limit int) (*SimilarImagesResult, error) {
+	imageName, err := app.resolveImageFile(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	by = strings.TrimSpace(strings.ToLower(by))
+	if by == "" {
+		by = SimilarByHash
+
+		if app.EmbeddingProvider != nil {
+			imageVectors, err := app.loadImageEmbeddings(db, imageName)
+			if err != nil {
+				return nil, err
+			}
+			if len(imageVectors) > 0 {
+				by = SimilarByEmbedding
+			}
+		}
+	}
+
+	result := &SimilarImagesResult{
+		By:   by,
+		Name: imageName,
+	}
+
+	switch by {
+	case SimilarByEmbedding:
+		result.Images, err = app.findSimilarImagesByEmbedding(db, imageName, limit)
+	case SimilarByHash:
+		result.Images, err = app.findSimilarImagesByHash(db, imageName, limit)
+	default:
+		return nil, fmt.Errorf("images cannot be compared by '%s'", by)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Images {
+		meta, err := app.GetImageMeta(db, result.Images[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		if meta != nil {
+			result.Images[i].Title = meta.Title
+		}
+	}
+
+	return result, nil
+}
+
+func (app *AppContext) findSimilarImagesByEmbedding(db *sql.DB, imageName string, limit int) ([]SimilarImage, error) {
+	if app.EmbeddingProvider == nil {
+		return nil, ErrEmbeddingsDisabled
+	}
+
+	imageVectors, err := app.loadImageEmbeddings(db, imageName)
+	if err != nil {
+		return nil, err
+	}
+	if len(imageVectors) == 0 {
+		return nil, fmt.Errorf("%w: '%s' has no embedding", ErrImageNotIndexed, imageName)
+	}
+
+	index, err := app.getEmbeddingIndex(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// best score of each image over the text and image embeddings;
+	// each image can have two entries and the image itself is found, too
+	scores := map[string]float32{}
+	for _, vector := range imageVectors {
+		for _, m := range index.Search(vector, 2*(limit+1)) {
+			if m.Value.name == imageName {
+				continue
+			}
+
+			score, ok := scores[m.Value.name]
+			if !ok || m.Score > score {
+				scores[m.Value.name] = m.Score
+			}
+		}
+	}
+
+	images := make([]SimilarImage, 0, len(scores))
+	for name, score := range scores {
+		images = append(images, SimilarImage{
+			Name:  name,
+			Score: max(score, 0),
+		})
+	}
+
+	return sortSimilarImages(images, limit), nil
+}
+
+func (app *AppContext) findSimilarImagesByHash(db *sql.DB, imageName string, limit int) ([]SimilarImage, error) {
+	// indexing is done by scanner, watcher and jobs,
+	// not within a request
+	var value sql.NullString
+	err := db.QueryRow("SELECT dhash FROM image_metadata WHERE file_path = ?;", imageName).Scan(&value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if !value.Valid {
+		return nil, fmt.Errorf("%w: '%s' has no perceptual hash", ErrImageNotIndexed, imageName)
+	}
+
+	hash, err := imagehash.Parse(value.String)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT m.file_path, m.dhash
+FROM image_metadata m INNER JOIN image_files f ON f.file_path = m.file_path
+WHERE m.dhash IS NOT NULL AND m.file_path <> ?;`, imageName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make([]SimilarImage, 0)
+	for rows.Next() {
+		var name, otherValue string
+		err = rows.Scan(&name, &otherValue)
+		if err != nil {
+			return nil, err
+		}
+
+		otherHash, err := imagehash.Parse(otherValue)
+		if err != nil {
+			continue
+		}
+
+		distance := hash.Distance(otherHash)
+		images = append(images, SimilarImage{
+			Distance: &distance,
+			Name:     name,
+			Score:    1 - float32(distance)/64,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sortSimilarImages(images, limit), nil
+}
+
+func (app *AppContext) loadImageEmbeddings(db *sql.DB, imageName string) ([][]float32, error) {
+	rows, err := db.Query(
+		"SELECT vector FROM image_embeddings WHERE file_path = ? AND model = ? ORDER BY kind;",
+		imageName, app.EmbeddingProvider.Model(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imageVectors := make([][]float32, 0)
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		vector, err := vectors.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+
+		imageVectors = append(imageVectors, vector)
+	}
+
+	return imageVectors, rows.Err()
+}
+
+func sortSimilarImages(images []SimilarImage, limit int) []SimilarImage {
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Score != images[j].Score {
+			return images[i].Score > images[j].Score
+		}
+		return images[i].Name < images[j].Name
+	})
+
+	return images[:min(limit, len(images))]
+}
@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build sqlite_fts5
+
+package types_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mkloubert/my-ai-gallery/types"
+)
+
+func TestFindSimilarImagesByHash(t *testing.T) {
+	app, db := newTestApp(t, &tagsVisionProvider{})
+
+	_, err := app.FindSimilarImages(db, "2024/photo.jpg", types.SimilarByHash, 10)
+	if !errors.Is(err, types.ErrImageNotIndexed) {
+		t.Errorf("got error %v before indexing, expected %v", err, types.ErrImageNotIndexed)
+	}
+
+	_, err = app.IndexImage(db, "2024/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := app.FindSimilarImages(db, "2024/photo.jpg", types.SimilarByHash, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.By != types.SimilarByHash {
+		t.Errorf("got by %q, expected %q", result.By, types.SimilarByHash)
+	}
+	if len(result.Images) != 0 {
+		t.Errorf("got %d similar images, expected none", len(result.Images))
+	}
+}
@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TagsJSONColumn is a SQL expression, which returns the sorted tags
+// of the image in `images` table as JSON array.
+const TagsJSONColumn = `(SELECT json_group_array(name) FROM (
+  SELECT t.name FROM image_tags it INNER JOIN tags t ON t.id = it.tag_id
+  WHERE it.image_id = images.id ORDER BY t.name
+))`
+
+// TagUsage stores a tag and the number of images using it.
+type TagUsage struct {
+	// Count stores the number of images.
+	Count int `json:"count"`
+	// Name stores the name of the tag.
+	Name string `json:"name"`
+}
+
+// GetTags returns all tags, which are used by at least one image.
+func (app *AppContext) GetTags(db *sql.DB) ([]TagUsage, error) {
+	rows, err := db.Query(`SELECT t.name, COUNT(*) AS usage_count
+FROM tags t
+INNER JOIN image_tags it ON it.tag_id = t.id
+GROUP BY t.id
+ORDER BY usage_count DESC, t.name;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]TagUsage, 0)
+	for rows.Next() {
+		var tag TagUsage
+		err = rows.Scan(&tag.Name, &tag.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// TagMode describes how tags of the vision provider are saved.
+type TagMode string
+
+const (
+	// TagModeMerge adds the new tags to the existing ones.
+	TagModeMerge TagMode = "merge"
+	// TagModeReplace replaces the existing tags.
+	TagModeReplace TagMode = "replace"
+)
+
+// ParseTagMode parses the name of a TagMode.
+// An empty value returns defaultMode.
+func ParseTagMode(value string, defaultMode TagMode) (TagMode, error) {
+	switch TagMode(strings.TrimSpace(strings.ToLower(value))) {
+	case "":
+		return defaultMode, nil
+	case TagModeMerge:
+		return TagModeMerge, nil
+	case TagModeReplace:
+		return TagModeReplace, nil
+	}
+
+	return "", fmt.Errorf("tag mode '%s' is not supported", value)
+}
+
+// NormalizeTags returns a sorted list of unique, trimmed
+// and lower case tags without empty items.
+func NormalizeTags(tags []string) []string {
+	tagList := make([]string, 0)
+
+	for _, t := range tags {
+		t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+		if t == "" {
+			continue
+		}
+
+		if !slices.Contains(tagList, t) {
+			tagList = append(tagList, t)
+		}
+	}
+
+	sort.Strings(tagList)
+
+	return tagList
+}
+
+// ParseTagsJSON parses the value of TagsJSONColumn.
+func ParseTagsJSON(value sql.NullString) []string {
+	tags := make([]string, 0)
+
+	if value.Valid {
+		json.Unmarshal([]byte(value.String), &tags)
+	}
+
+	return NormalizeTags(tags)
+}
+
+// SetImageTags replaces the tags of an image.
+func SetImageTags(tx *sql.Tx, imageId int64, tags []string) error {
+	_, err := tx.Exec("DELETE FROM image_tags WHERE image_id = ?;", imageId)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range NormalizeTags(tags) {
+		var tagId int64
+		err = tx.QueryRow(`INSERT INTO tags (name) VALUES (?)
+ON CONFLICT(name) DO UPDATE SET name = excluded.name
+RETURNING id;`, name).Scan(&tagId)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("INSERT OR IGNORE INTO image_tags (image_id, tag_id) VALUES (?, ?);", imageId, tagId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return deleteUnusedTags(tx)
+}
+
+func deleteUnusedTags(tx *sql.Tx) error {
+	_, err := tx.Exec("DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM image_tags);")
+	return err
+}
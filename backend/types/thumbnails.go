@@ -0,0 +1,290 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/mkloubert/my-ai-gallery/metadata"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// maxThumbnailPixels is the maximum number of pixels of an image,
+// which is decoded for a thumbnail.
+const maxThumbnailPixels = 100_000_000
+
+// ErrImageTooLarge is returned if an image has too many pixels
+// to create a thumbnail of it.
+var ErrImageTooLarge = errors.New("image has too many pixels")
+
+// thumbnailLocks stores a mutex for each image and size, which is
+// never removed, so that all callers always share the same one.
+var thumbnailLocks sync.Map
+
+// GetDefaultThumbnailSize returns the thumbnail size
+// used if no size has been requested.
+func (app *AppContext) GetDefaultThumbnailSize() int {
+	return app.Config.Thumbnails.Sizes[0]
+}
+
+// GetThumbnailUrl returns the API URL of the default thumbnail of an image.
+func (app *AppContext) GetThumbnailUrl(imageName string) string {
+	return fmt.Sprintf("%s/thumb?size=%d", ImageUrl(imageName), app.GetDefaultThumbnailSize())
+}
+
+// GetThumbnailFolder returns the full path of the folder,
+// where thumbnails are cached.
+func (app *AppContext) GetThumbnailFolder() string {
+	return filepath.Join(app.GetImageFolder(), ".maig", "thumbnails")
+}
+
+// GetThumbnail returns the full path of a cached thumbnail
+// of an image and creates it, if needed.
+func (app *AppContext) GetThumbnail(imageName string, size int) (string, error) {
+	if !slices.Contains(app.Config.Thumbnails.Sizes, size) {
+		return "", fmt.Errorf("thumbnail size %d is not supported", size)
+	}
+
+	imageName, fullPath, err := app.ResolveImagePath(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", err
+	}
+
+	// the key contains size and modification time of the original,
+	// so a changed file gets a new thumbnail
+	prefix := thumbnailPrefix(imageName, size)
+	thumbnailFile := filepath.Join(
+		app.GetThumbnailFolder(),
+		fmt.Sprintf("%s_%d_%d.jpg", prefix, info.ModTime().UnixNano(), info.Size()),
+	)
+
+	lock, _ := thumbnailLocks.LoadOrStore(prefix, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, err := os.Stat(thumbnailFile); err == nil {
+		return thumbnailFile, nil
+	}
+
+	fmt.Fprintf(app.Stdout, "Creating thumbnail of file '%s' with size %d ...%s", fullPath, size, app.EOL)
+
+	err = createThumbnail(fullPath, thumbnailFile, size)
+	if err != nil {
+		return "", err
+	}
+
+	// remove outdated versions
+	outdatedFiles, _ := filepath.Glob(filepath.Join(app.GetThumbnailFolder(), prefix+"_*.jpg"))
+	for _, f := range outdatedFiles {
+		if f != thumbnailFile {
+			os.Remove(f)
+		}
+	}
+
+	return thumbnailFile, nil
+}
+
+// RemoveThumbnails removes all cached thumbnails of an image.
+func (app *AppContext) RemoveThumbnails(imageName string) error {
+	imageName, err := CleanImageName(imageName)
+	if err != nil {
+		return err
+	}
+
+	for _, size := range app.Config.Thumbnails.Sizes {
+		files, err := filepath.Glob(filepath.Join(app.GetThumbnailFolder(), thumbnailPrefix(imageName, size)+"_*.jpg"))
+		if err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			err = os.Remove(f)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func createThumbnail(sourceFile string, targetFile string, size int) error {
+	file, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// check the dimensions first, so a small file cannot
+	// make the server allocate gigabytes of memory
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return err
+	}
+	if int64(config.Width)*int64(config.Height) > maxThumbnailPixels {
+		return fmt.Errorf("%w: '%s' has %dx%d pixels", ErrImageTooLarge, sourceFile, config.Width, config.Height)
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	src, _, err := image.Decode(file)
+	if err != nil {
+		return err
+	}
+
+	// rotate and mirror, like the image is displayed
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	if meta, err := metadata.Read(file); err == nil && meta.Orientation != nil {
+		src = orientImage(src, *meta.Orientation)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 {
+		return fmt.Errorf("image '%s' has no pixels", sourceFile)
+	}
+
+	// keep aspect ratio and never upscale
+	newWidth, newHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			newWidth = size
+			newHeight = max(1, height*size/width)
+		} else {
+			newHeight = size
+			newWidth = max(1, width*size/height)
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	// JPEG has no alpha channel
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	err = os.MkdirAll(filepath.Dir(targetFile), 0o755)
+	if err != nil {
+		return err
+	}
+
+	// write to temp file first, so nobody reads a half written thumbnail
+	tempFile, err := os.CreateTemp(filepath.Dir(targetFile), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	err = jpeg.Encode(tempFile, dst, &jpeg.Options{Quality: 85})
+	if err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), targetFile)
+}
+
+// orientImage returns a copy of src, which has been rotated and mirrored
+// according to an EXIF orientation from 1 to 8.
+func orientImage(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		// rotated by 90 degrees
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var srcX, srcY int
+			switch orientation {
+			case 2: // mirrored horizontally
+				srcX, srcY = width-1-x, y
+			case 3: // rotated by 180 degrees
+				srcX, srcY = width-1-x, height-1-y
+			case 4: // mirrored vertically
+				srcX, srcY = x, height-1-y
+			case 5: // mirrored along the top-left diagonal
+				srcX, srcY = y, x
+			case 6: // rotated by 90 degrees clockwise
+				srcX, srcY = y, height-1-x
+			case 7: // mirrored along the top-right diagonal
+				srcX, srcY = width-1-y, height-1-x
+			case 8: // rotated by 90 degrees counter-clockwise
+				srcX, srcY = width-1-y, x
+			}
+
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], rgba.Pix[rgba.PixOffset(srcX, srcY):rgba.PixOffset(srcX, srcY)+4])
+		}
+	}
+
+	return dst
+}
+
+func thumbnailPrefix(imageName string, size int) string {
+	sum := sha256.Sum256([]byte(imageName))
+	hash := hex.EncodeToString(sum[:])
+
+	// use sub folders, so a folder does not contain too many files
+	return filepath.Join(hash[:2], fmt.Sprintf("%s_%d", hash, size))
+}
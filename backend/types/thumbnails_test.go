@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOrientImage(t *testing.T) {
+	// 3x2 pixels, where each pixel has its own red value:
+	// 0 1 2
+	// 3 4 5
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(y*3 + x), A: 255})
+		}
+	}
+
+	tests := map[int][][]uint8{
+		1: {{0, 1, 2}, {3, 4, 5}},
+		2: {{2, 1, 0}, {5, 4, 3}},
+		3: {{5, 4, 3}, {2, 1, 0}},
+		4: {{3, 4, 5}, {0, 1, 2}},
+		5: {{0, 3}, {1, 4}, {2, 5}},
+		6: {{3, 0}, {4, 1}, {5, 2}},
+		7: {{5, 2}, {4, 1}, {3, 0}},
+		8: {{2, 5}, {1, 4}, {0, 3}},
+	}
+	for orientation, expected := range tests {
+		dst := orientImage(src, orientation)
+
+		bounds := dst.Bounds()
+		if bounds.Dx() != len(expected[0]) || bounds.Dy() != len(expected) {
+			t.Errorf("orientation %d: got size %dx%d", orientation, bounds.Dx(), bounds.Dy())
+			continue
+		}
+
+		for y, row := range expected {
+			for x, value := range row {
+				r, _, _, _ := dst.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+				if uint8(r>>8) != value {
+					t.Errorf("orientation %d: pixel %d,%d is %d, expected %d", orientation, x, y, r>>8, value)
+				}
+			}
+		}
+	}
+}
+
+func TestCreateThumbnailRejectsTooManyPixels(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// claim 20000x20000 pixels in the IHDR chunk, which follows
+	// the 8 bytes signature, length and type
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:20], 20000)
+	binary.BigEndian.PutUint32(data[20:24], 20000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	dir := t.TempDir()
+	sourceFile := filepath.Join(dir, "huge.png")
+	err = os.WriteFile(sourceFile, data, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = createThumbnail(sourceFile, filepath.Join(dir, "thumb.jpg"), 256)
+	if !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("got error %v, expected %v", err, ErrImageTooLarge)
+	}
+}
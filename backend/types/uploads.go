@@ -0,0 +1,652 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnsupportedMediaType is returned if an uploaded file is no image.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrUploadNotFound is returned if an upload does not exist.
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrUploadOffsetMismatch is returned if a chunk does not start at the current offset of an upload.
+	ErrUploadOffsetMismatch = errors.New("upload offset mismatch")
+	// ErrUploadTooLarge is returned if an uploaded file is larger than allowed.
+	ErrUploadTooLarge = errors.New("upload too large")
+)
+
+var uploadLocks sync.Map
+
+// Upload stores the state of a resumable upload.
+type Upload struct {
+	// CreatedAt stores the time the upload has been created.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// FilePath stores the relative path of the final image, if finished.
+	FilePath string `json:"file_path,omitempty"`
+	// Filename stores the requested file name.
+	Filename string `json:"filename"`
+	// FinishedAt stores the time the upload has been finished.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Folder stores the relative path of the target folder.
+	Folder string `json:"folder"`
+	// Id stores the ID of the upload.
+	Id string `json:"id"`
+	// Length stores the total size in bytes.
+	Length int64 `json:"length"`
+	// Offset stores the number of bytes received so far.
+	Offset int64 `json:"offset"`
+	// Tag stores if the image should be tagged after the upload.
+	Tag bool `json:"tag"`
+}
+
+// GetUploadFolder returns the full path of the folder for temporary upload data.
+func (app *AppContext) GetUploadFolder() string {
+	return filepath.Join(app.GetImageFolder(), ".maig", "uploads")
+}
+
+// AppendUploadChunk appends a chunk of data to a resumable upload, which
+// must start at offset. The image is moved to its final location,
+// when all data has been received.
+func (app *AppContext) AppendUploadChunk(db *sql.DB, id string, offset int64, chunk io.Reader) (*Upload, error) {
+	unlock := lockUpload(id)
+	defer unlock()
+
+	upload, err := app.GetUpload(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.FinishedAt != nil || upload.Offset != offset {
+		return upload, fmt.Errorf("%w: expected %d, got %d", ErrUploadOffsetMismatch, upload.Offset, offset)
+	}
+
+	dataFile := app.getUploadDataFile(id)
+
+	file, err := os.OpenFile(dataFile, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// never accept more than announced
+	written, copyErr := io.Copy(file, io.LimitReader(chunk, upload.Length-offset))
+
+	err = file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// keep what has been received, even if the connection broke
+	upload.Offset += written
+	_, err = db.Exec("UPDATE uploads SET upload_offset = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;", upload.Offset, id)
+	if err != nil {
+		return nil, err
+	}
+	if copyErr != nil {
+		return upload, copyErr
+	}
+
+	if upload.Offset < upload.Length {
+		return upload, nil
+	}
+
+	filePath, err := app.StoreImageFile(dataFile, upload.Folder, upload.Filename)
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		// there is no way to continue such an upload
+		app.deleteUpload(db, id)
+	}
+	if err != nil {
+		return upload, err
+	}
+
+	upload.FilePath = filePath
+	_, err = db.Exec("UPDATE uploads SET file_path = ?, finished_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ?;", filePath, id)
+	if err != nil {
+		return upload, err
+	}
+
+	// later chunks are rejected by the finish time, not by the lock
+	uploadLocks.Delete(id)
+
+	if upload.Tag {
+		_, err = app.Jobs.CreateJob("tag", JobScopeNames, []string{filePath})
+		if err != nil {
+			return upload, err
+		}
+	}
+
+	return app.GetUpload(db, id)
+}
+
+// CreateUpload creates a new resumable upload.
+func (app *AppContext) CreateUpload(db *sql.DB, filename string, folder string, length int64, tag bool) (*Upload, error) {
+	if length < 1 {
+		return nil, fmt.Errorf("upload length must be at least 1")
+	}
+	if maxSize := app.Config.Uploads.MaxSize; maxSize > 0 && length > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes, allowed are %d", ErrUploadTooLarge, length, maxSize)
+	}
+
+	filename, folder, err := app.checkUploadTarget(filename, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 16)
+	_, err = rand.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(buf)
+
+	err = os.MkdirAll(app.GetUploadFolder(), 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(
+		"INSERT INTO uploads (id, filename, folder, upload_length, tag) VALUES (?, ?, ?, ?, ?);",
+		id, filename, folder, length, tag,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(app.Stdout, "Created upload '%s' for '%s' (%d bytes)%s", id, path.Join(folder, filename), length, app.EOL)
+
+	return app.GetUpload(db, id)
+}
+
+// CleanupUploads removes unfinished uploads, which have not received any data
+// for the configured expiration time, together with their data, and the
+// bookkeeping of finished ones. Returns the number of removed uploads.
+func (app *AppContext) CleanupUploads(db *sql.DB) (int, error) {
+	expiration := app.Config.Uploads.Expiration
+	if expiration <= 0 {
+		return 0, nil
+	}
+
+	rows, err := db.Query(
+		"SELECT id FROM uploads WHERE COALESCE(updated_at, created_at) < datetime('now', ?);",
+		fmt.Sprintf("-%d seconds", int64(expiration.Seconds())),
+	)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		deleted, err := app.deleteExpiredUpload(db, id, expiration)
+		if err != nil {
+			return count, err
+		}
+		if deleted {
+			count++
+		}
+	}
+
+	// data, which is left over from crashes
+	entries, err := os.ReadDir(app.GetUploadFolder())
+	if err != nil && !os.IsNotExist(err) {
+		return count, err
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < expiration {
+			continue
+		}
+
+		name := entry.Name()
+		if id, ok := strings.CutSuffix(name, ".part"); ok {
+			_, err = app.GetUpload(db, id)
+			if !errors.Is(err, ErrUploadNotFound) {
+				continue
+			}
+		} else if !strings.HasPrefix(name, "multipart-") {
+			continue
+		}
+
+		os.Remove(filepath.Join(app.GetUploadFolder(), name))
+	}
+
+	return count, nil
+}
+
+// DeleteUpload removes a resumable upload and its data.
+func (app *AppContext) DeleteUpload(db *sql.DB, id string) error {
+	unlock := lockUpload(id)
+	defer unlock()
+
+	return app.deleteUpload(db, id)
+}
+
+// StartUploadCleanup runs CleanupUploads() in background every hour.
+func (app *AppContext) StartUploadCleanup() {
+	if app.Config.Uploads.Expiration <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			app.cleanupUploads()
+
+			time.Sleep(time.Hour)
+		}
+	}()
+}
+
+// GetUpload loads a resumable upload by its ID.
+func (app *AppContext) GetUpload(db *sql.DB, id string) (*Upload, error) {
+	var upload Upload
+	var filePath sql.NullString
+	var createdAt, finishedAt sql.NullTime
+	err := db.QueryRow(`SELECT id, filename, folder, upload_length, upload_offset, tag, file_path, created_at, finished_at
+FROM uploads WHERE id = ?;`, id).Scan(
+		&upload.Id, &upload.Filename, &upload.Folder, &upload.Length, &upload.Offset,
+		&upload.Tag, &filePath, &createdAt, &finishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: '%s'", ErrUploadNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	upload.FilePath = filePath.String
+	if createdAt.Valid {
+		upload.CreatedAt = &createdAt.Time
+	}
+	if finishedAt.Valid {
+		upload.FinishedAt = &finishedAt.Time
+	}
+
+	return &upload, nil
+}
+
+// StagedImage stores an uploaded image, which has been checked,
+// but not been stored in the image folder yet.
+type StagedImage struct {
+	filename string
+	folder   string
+	tempFile string
+}
+
+// Remove removes the temporary file of the image, if it has not been stored.
+func (s *StagedImage) Remove() {
+	os.Remove(s.tempFile)
+}
+
+// StageImage saves the data of an uploaded image in a temporary file
+// and checks that it is an image. The image is stored with StoreStagedImages().
+func (app *AppContext) StageImage(data io.Reader, folder string, filename string) (*StagedImage, error) {
+	filename, folder, err := app.checkUploadTarget(filename, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(app.GetUploadFolder(), 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	tempFile, err := os.CreateTemp(app.GetUploadFolder(), "multipart-*")
+	if err != nil {
+		return nil, err
+	}
+
+	staged := &StagedImage{
+		filename: filename,
+		folder:   folder,
+		tempFile: tempFile.Name(),
+	}
+
+	// never read more than allowed
+	if maxSize := app.Config.Uploads.MaxSize; maxSize > 0 {
+		var written int64
+		written, err = io.Copy(tempFile, io.LimitReader(data, maxSize+1))
+		if err == nil && written > maxSize {
+			err = fmt.Errorf("%w: '%s' is larger than %d bytes", ErrUploadTooLarge, filename, maxSize)
+		}
+	} else {
+		_, err = io.Copy(tempFile, data)
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = checkImageFile(staged.tempFile, filename)
+	}
+	if err != nil {
+		staged.Remove()
+		return nil, err
+	}
+
+	return staged, nil
+}
+
+// StoreStagedImages stores images in the image folder and returns their
+// relative paths. If one of them fails, the ones stored before are deleted
+// again, so that either all or no images are stored.
+func (app *AppContext) StoreStagedImages(images []*StagedImage) ([]string, error) {
+	names := make([]string, 0, len(images))
+	for _, image := range images {
+		name, err := app.StoreImageFile(image.tempFile, image.folder, image.filename)
+		if err != nil {
+			return nil, errors.Join(err, app.deleteStoredImages(names))
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// StoreImageFile moves a temporary file into a folder of the image folder,
+// after checking that it is an image, and indexes it. If the file name already
+// exists, a suffix like ` (1)` is added. Returns the relative path of the new file.
+func (app *AppContext) StoreImageFile(tempFile string, folder string, filename string) (string, error) {
+	filename, folder, err := app.checkUploadTarget(filename, folder)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkImageFile(tempFile, filename)
+	if err != nil {
+		return "", err
+	}
+
+	targetFolder, err := app.ensureImageFolder(folder)
+	if err != nil {
+		return "", err
+	}
+
+	ext := path.Ext(filename)
+	baseName := strings.TrimSuffix(filename, ext)
+
+	for i := 0; i < 10000; i++ {
+		name := filename
+		if i > 0 {
+			name = fmt.Sprintf("%s (%d)%s", baseName, i, ext)
+		}
+
+		targetFile := filepath.Join(targetFolder, name)
+		if app.isDatabaseFile(targetFile) {
+			return "", fmt.Errorf("%w: '%s' is reserved for the database", ErrInvalidImagePath, name)
+		}
+
+		// reserve the name, so parallel uploads do not overwrite each other
+		reservation, err := os.OpenFile(targetFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		reservation.Close()
+
+		err = os.Chmod(tempFile, 0o644)
+		if err == nil {
+			err = os.Rename(tempFile, targetFile)
+		}
+		if err != nil {
+			os.Remove(targetFile)
+			return "", err
+		}
+
+		relPath := path.Join(folder, name)
+
+		fmt.Fprintf(app.Stdout, "Stored new image '%s'%s", relPath, app.EOL)
+
+		// the image list is read from the index
+		err = app.indexNewImage(relPath)
+		if err != nil {
+			fmt.Fprintf(app.Stderr, "[WARN] Could not index '%s': %s%s", relPath, err.Error(), app.EOL)
+		}
+
+		return relPath, nil
+	}
+
+	return "", fmt.Errorf("no free file name for '%s'", filename)
+}
+
+func (app *AppContext) deleteStoredImages(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var errs []error
+	for _, name := range names {
+		fmt.Fprintf(app.Stdout, "Removing stored image '%s' of failed upload ...%s", name, app.EOL)
+
+		errs = append(errs, app.DeleteImage(db, name))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (app *AppContext) indexNewImage(imageName string) error {
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = app.IndexImage(db, imageName)
+	return err
+}
+
+// checkImageFile returns ErrUnsupportedMediaType, if the content of a file is no image.
+func checkImageFile(fullPath string, filename string) error {
+	mimeType, err := DetectMimeType(fullPath)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return fmt.Errorf("%w: '%s' is '%s'", ErrUnsupportedMediaType, filename, mimeType)
+	}
+
+	return nil
+}
+
+func (app *AppContext) checkUploadTarget(filename string, folder string) (string, string, error) {
+	// only use the last part of the name
+	filename = path.Base(strings.ReplaceAll(strings.TrimSpace(filename), "\\", "/"))
+	if filename == "." || filename == "/" {
+		return "", "", fmt.Errorf("%w: file name is missing", ErrInvalidImagePath)
+	}
+
+	_, err := CleanImageName(filename)
+	if err != nil {
+		return "", "", err
+	}
+
+	folder = strings.TrimSpace(folder)
+	if folder != "" {
+		folder, err = CleanImageName(folder)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	targetPath, err := app.GetImagePath(path.Join(folder, filename))
+	if err != nil {
+		return "", "", err
+	}
+	if app.isDatabaseFile(targetPath) {
+		return "", "", fmt.Errorf("%w: '%s' is reserved for the database", ErrInvalidImagePath, filename)
+	}
+
+	return filename, folder, nil
+}
+
+// ensureImageFolder creates a folder inside the image folder step by step,
+// so that no symbolic link can lead outside of it, and returns its full path.
+func (app *AppContext) ensureImageFolder(folder string) (string, error) {
+	fullPath, err := filepath.EvalSymlinks(app.GetImageFolder())
+	if err != nil {
+		return "", err
+	}
+	if folder == "" || folder == "." {
+		return fullPath, nil
+	}
+
+	current := ""
+	for _, segment := range strings.Split(folder, "/") {
+		parentPath := fullPath
+		current = path.Join(current, segment)
+
+		_, fullPath, err = app.ResolveImagePath(current)
+		if errors.Is(err, ErrImageNotFound) {
+			// create inside the already checked parent
+			err = os.Mkdir(filepath.Join(parentPath, segment), 0o755)
+			if err != nil && !os.IsExist(err) {
+				return "", err
+			}
+
+			_, fullPath, err = app.ResolveImagePath(current)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%w: '%s' is no folder", ErrInvalidImagePath, folder)
+	}
+
+	return fullPath, nil
+}
+
+func (app *AppContext) cleanupUploads() {
+	db, err := app.OpenImageDatabase()
+	if err != nil {
+		fmt.Fprintf(app.Stderr, "[WARN] Could not clean up uploads: %s%s", err.Error(), app.EOL)
+		return
+	}
+	defer db.Close()
+
+	count, err := app.CleanupUploads(db)
+	if err != nil {
+		fmt.Fprintf(app.Stderr, "[WARN] Could not clean up uploads: %s%s", err.Error(), app.EOL)
+	}
+	if count > 0 {
+		fmt.Fprintf(app.Stdout, "Removed %d expired uploads%s", count, app.EOL)
+	}
+}
+
+// deleteExpiredUpload deletes an upload, if it is still expired, after it
+// has been locked, so an upload, which receives a chunk right now, is kept.
+func (app *AppContext) deleteExpiredUpload(db *sql.DB, id string, expiration time.Duration) (bool, error) {
+	unlock := lockUpload(id)
+	defer unlock()
+
+	var expired bool
+	err := db.QueryRow(
+		"SELECT COALESCE(updated_at, created_at) < datetime('now', ?) FROM uploads WHERE id = ?;",
+		fmt.Sprintf("-%d seconds", int64(expiration.Seconds())), id,
+	).Scan(&expired)
+	if err == sql.ErrNoRows || (err == nil && !expired) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, app.deleteUpload(db, id)
+}
+
+func (app *AppContext) deleteUpload(db *sql.DB, id string) error {
+	_, err := app.GetUpload(db, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("DELETE FROM uploads WHERE id = ?;", id)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(app.getUploadDataFile(id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// later chunks are rejected, because the upload does not exist anymore
+	uploadLocks.Delete(id)
+
+	return nil
+}
+
+func (app *AppContext) getUploadDataFile(id string) string {
+	return filepath.Join(app.GetUploadFolder(), id+".part")
+}
+
+// lockUpload locks the upload with an ID and returns the function to unlock it.
+func lockUpload(id string) func() {
+	lock, _ := uploadLocks.LoadOrStore(id, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+
+	return mutex.Unlock
+}
@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// ImageInformation stores the structured information about an image
+// generated by a vision model.
+type ImageInformation struct {
+	// DetailedDescription stores a detailed description what is in the image.
+	DetailedDescription string `json:"detailed_description"`
+	// Tags stores words or small texts that categorize the image.
+	Tags []string `json:"tags"`
+	// Title stores a short and descriptive title for the image.
+	Title string `json:"title"`
+}
+
+// VisionProvider is a backend that is able to describe images.
+type VisionProvider interface {
+	// DescribeImage describes an image and returns structured information about it.
+	DescribeImage(ctx context.Context, request *VisionRequest) (*ImageInformation, error)
+	// Name returns the name of the provider.
+	Name() string
+}
+
+// VisionRequest stores the data for a DescribeImage() call of a VisionProvider.
+type VisionRequest struct {
+	// Data stores the binary data of the image.
+	Data []byte
+	// Filename stores the relative path of the image file.
+	Filename string
+	// MimeType stores the MIME type of Data.
+	MimeType string
+	// Prompt stores the prompt for the model.
+	Prompt string
+}
+
+// VisionSettings stores the settings for a vision provider.
+type VisionSettings struct {
+	// ApiKey stores the optional API key for the model server.
+	ApiKey string `yaml:"api_key"`
+	// ContextTemplate stores the Go template for the context block of the prompt.
+	ContextTemplate string `yaml:"context_template"`
+	// Model stores the name of the model.
+	Model string `yaml:"model"`
+	// Prompt stores the prompt that is sent with every image.
+	Prompt string `yaml:"prompt"`
+	// PromptContext stores if metadata like capture date, GPS position
+	// and camera is added to the prompt.
+	PromptContext bool `yaml:"prompt_context"`
+	// Provider stores the name of the provider, like `ollama`, `openai` or `fake`.
+	Provider string `yaml:"provider"`
+	// TagMode stores how new tags are saved, `replace` or `merge`.
+	TagMode TagMode `yaml:"tag_mode"`
+	// Temperature stores the temperature for the model.
+	Temperature float64 `yaml:"temperature"`
+	// Timeout stores the maximum duration of a request to the model server.
+	Timeout time.Duration `yaml:"timeout"`
+	// Url stores the base URL of the model server.
+	Url string `yaml:"url"`
+}
+
+// ImageInformationSchema returns the JSON schema for the answer
+// of a vision model.
+func ImageInformationSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"image_information"},
+		"properties": map[string]any{
+			"image_information": map[string]any{
+				"type":        "object",
+				"description": "Information about the image.",
+				"required":    []string{"detailed_description", "tags", "title"},
+				"properties": map[string]any{
+					"detailed_description": map[string]any{
+						"description": "A detailed description what is in the image.",
+						"type":        "string",
+					},
+					"tags": map[string]any{
+						"type":     "array",
+						"minItems": 1,
+						"maxItems": 10,
+						"items": map[string]any{
+							"type":        "string",
+							"description": "Word or small text that categorized the image.",
+						},
+					},
+					"title": map[string]any{
+						"description": "A short and descriptive title for the image.",
+						"type":        "string",
+					},
+				},
+			},
+		},
+	}
+}
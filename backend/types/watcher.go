@@ -0,0 +1,353 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileWatcher watches the image folder and indexes new, changed, moved
+// and deleted files, after no more changes have been reported for a while.
+type FileWatcher struct {
+	app          *AppContext
+	db           *sql.DB
+	ignored      map[string]bool
+	imageFolder  string
+	mutex        sync.Mutex
+	pending      map[string]bool
+	pendingSince time.Time
+	timer        *time.Timer
+	watcher      *fsnotify.Watcher
+}
+
+// NewFileWatcher creates a new FileWatcher for the image folder.
+func NewFileWatcher(app *AppContext) *FileWatcher {
+	return &FileWatcher{
+		app:     app,
+		pending: make(map[string]bool),
+	}
+}
+
+// Start starts watching the image folder and a full scan, which is repeated
+// in the configured interval. If the folder cannot be watched, for example
+// because of the inotify limits, changes are only found by the full scans.
+func (w *FileWatcher) Start() error {
+	// the image folder itself can be a symbolic link
+	imageFolder, err := filepath.EvalSymlinks(w.app.GetImageFolder())
+	if err != nil {
+		return err
+	}
+
+	db, err := w.app.OpenImageDatabase()
+	if err != nil {
+		return err
+	}
+
+	w.db = db
+	w.ignored = w.app.getDatabaseFiles()
+	w.imageFolder = imageFolder
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		w.watcher = watcher
+
+		count := w.addFolders(imageFolder)
+		fmt.Fprintf(w.app.Stdout, "Watching %d folders in '%s' ...%s", count, imageFolder, w.app.EOL)
+
+		go w.watch()
+	} else {
+		fmt.Fprintf(w.app.Stderr, "[WARN] Could not watch '%s': %s%s", imageFolder, err.Error(), w.app.EOL)
+	}
+
+	go w.rescan()
+
+	return nil
+}
+
+// addFolders watches a folder and its sub folders and returns their number.
+func (w *FileWatcher) addFolders(folder string) int {
+	count := 0
+
+	filepath.WalkDir(folder, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if fullPath != w.imageFolder && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		err = w.watcher.Add(fullPath)
+		if err != nil {
+			fmt.Fprintf(w.app.Stderr, "[WARN] Could not watch '%s': %s%s", fullPath, err.Error(), w.app.EOL)
+			return filepath.SkipAll
+		}
+
+		count++
+		return nil
+	})
+
+	return count
+}
+
+func (w *FileWatcher) flush() {
+	w.mutex.Lock()
+	names := w.pending
+	w.pending = make(map[string]bool)
+	w.timer = nil
+	w.mutex.Unlock()
+
+	fileScanMutex.Lock()
+	defer fileScanMutex.Unlock()
+
+	// deleted files first, so moved files can be found
+	removed := 0
+	existing := make([]string, 0, len(names))
+	for name := range names {
+		_, err := os.Lstat(filepath.Join(w.imageFolder, filepath.FromSlash(name)))
+		if errors.Is(err, fs.ErrNotExist) {
+			count, err := w.markDeleted(name)
+			if err != nil {
+				fmt.Fprintf(w.app.Stderr, "[WARN] Could not remove '%s': %s%s", name, err.Error(), w.app.EOL)
+			}
+			removed += count
+		} else if err == nil {
+			existing = append(existing, name)
+		}
+	}
+	sort.Strings(existing)
+
+	checked := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, name := range existing {
+		fullPath := filepath.Join(w.imageFolder, filepath.FromSlash(name))
+
+		filepath.WalkDir(fullPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if path != fullPath && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || !d.Type().IsRegular() || w.ignored[path] {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(w.imageFolder, path)
+			if err != nil {
+				return nil
+			}
+			relPath = filepath.ToSlash(relPath)
+
+			if !seen[relPath] {
+				seen[relPath] = true
+
+				if w.indexFile(relPath) {
+					checked = append(checked, relPath)
+				}
+			}
+			return nil
+		})
+	}
+
+	if removed > 0 || len(checked) > 0 {
+		fmt.Fprintf(w.app.Stdout, "Indexed %d changed files, marked %d files as deleted%s", len(checked), removed, w.app.EOL)
+	}
+
+	w.tagImages(checked)
+}
+
+func (w *FileWatcher) handleEvent(event fsnotify.Event) {
+	if event.Op == fsnotify.Chmod || w.ignored[event.Name] {
+		return
+	}
+
+	relPath, err := filepath.Rel(w.imageFolder, event.Name)
+	if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+		return
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	for _, segment := range strings.Split(relPath, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return
+		}
+	}
+
+	if event.Has(fsnotify.Create) {
+		info, err := os.Lstat(event.Name)
+		if err == nil && info.IsDir() {
+			// files can be copied, before the new folder is watched,
+			// so the whole folder is checked
+			w.addFolders(event.Name)
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pending[relPath] = true
+
+	debounce := w.app.Config.Watcher.Debounce
+	if w.timer == nil {
+		w.pendingSince = time.Now()
+		w.timer = time.AfterFunc(debounce, w.flush)
+	} else if time.Since(w.pendingSince) < 10*debounce {
+		// large copy operations are indexed in parts
+		w.timer.Reset(debounce)
+	}
+}
+
+// indexFile updates the inventory entry and the metadata of a file
+// and returns false, if this failed.
+func (w *FileWatcher) indexFile(name string) bool {
+	entry, err := w.app.IndexFile(w.db, name)
+	if err == nil && strings.HasPrefix(entry.MimeType, "image/") {
+		_, err = w.app.IndexImage(w.db, name)
+	}
+	if err != nil {
+		fmt.Fprintf(w.app.Stderr, "[WARN] Could not index '%s': %s%s", name, err.Error(), w.app.EOL)
+		return false
+	}
+
+	return true
+}
+
+// markDeleted marks a file or all files of a folder as deleted
+// and returns their number.
+func (w *FileWatcher) markDeleted(name string) (int, error) {
+	result, err := w.db.Exec(
+		`UPDATE files SET deleted_at = CURRENT_TIMESTAMP WHERE deleted_at IS NULL AND (file_path = ? OR file_path LIKE ? ESCAPE '\');`,
+		name, escapeLike(name)+"/%",
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	count, _ := result.RowsAffected()
+	if count > 0 {
+		invalidateEmbeddingIndex()
+	}
+
+	return int(count), nil
+}
+
+func (w *FileWatcher) rescan() {
+	for {
+		w.scan()
+
+		interval := w.app.Config.Watcher.RescanInterval
+		if interval <= 0 {
+			return
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (w *FileWatcher) scan() {
+	scan, err := w.app.IndexImages()
+	if err != nil {
+		fmt.Fprintf(w.app.Stderr, "[ERROR] Indexing images failed: %s%s", err.Error(), w.app.EOL)
+		return
+	}
+
+	w.tagImages(scan.Updated)
+}
+
+// tagImages creates a job, which tags the untagged and stale images
+// of names, if this is enabled.
+func (w *FileWatcher) tagImages(names []string) {
+	if !w.app.Config.Watcher.AutoTag || w.app.Jobs == nil || len(names) == 0 {
+		return
+	}
+
+	namesJSON, err := json.Marshal(names)
+	if err != nil {
+		return
+	}
+
+	rows, err := w.db.Query(`SELECT f.file_path FROM image_files f
+LEFT JOIN images ON images.file_path = f.file_path
+WHERE f.file_path IN (SELECT value FROM json_each(?)) AND `+imageStatusColumn+` <> ?
+ORDER BY f.file_path;`, string(namesJSON), ImageStatusFresh)
+	if err != nil {
+		fmt.Fprintf(w.app.Stderr, "[WARN] Could not find images to tag: %s%s", err.Error(), w.app.EOL)
+		return
+	}
+	defer rows.Close()
+
+	imageNames := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if rows.Scan(&name) == nil {
+			imageNames = append(imageNames, name)
+		}
+	}
+	rows.Close()
+
+	if len(imageNames) == 0 {
+		return
+	}
+
+	_, err = w.app.Jobs.CreateJob("tag", JobScopeNames, imageNames)
+	if err != nil {
+		fmt.Fprintf(w.app.Stderr, "[WARN] Could not create tag job: %s%s", err.Error(), w.app.EOL)
+	}
+}
+
+func (w *FileWatcher) watch() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			w.handleEvent(event)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			fmt.Fprintf(w.app.Stderr, "[WARN] Watcher: %s%s", err.Error(), w.app.EOL)
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				// changes have been lost
+				go w.scan()
+			}
+		}
+	}
+}
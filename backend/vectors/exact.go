@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package vectors
+
+import (
+	"sort"
+)
+
+// ExactIndex compares the query with every entry, which is
+// fast enough for some ten thousand vectors.
+type ExactIndex[T any] struct {
+	entries []Entry[T]
+}
+
+// NewExactIndex creates a new ExactIndex. Entries with another
+// length than the first one are ignored.
+func NewExactIndex[T any](entries []Entry[T]) *ExactIndex[T] {
+	return &ExactIndex[T]{
+		entries: withSameLength(entries),
+	}
+}
+
+// Search implements the method of Index interface.
+func (index *ExactIndex[T]) Search(query []float32, limit int) []Match[T] {
+	return searchEntries(index.entries, nil, query, limit)
+}
+
+// Size implements the method of Index interface.
+func (index *ExactIndex[T]) Size() int {
+	return len(index.entries)
+}
+
+// searchEntries returns the best matches of the entries with the
+// indexes candidates or of all entries, if candidates is nil.
+func searchEntries[T any](entries []Entry[T], candidates []int, query []float32, limit int) []Match[T] {
+	matches := make([]Match[T], 0)
+	if limit < 1 || len(entries) == 0 || len(query) != len(entries[0].Vector) {
+		return matches
+	}
+
+	add := func(entry *Entry[T]) {
+		matches = append(matches, Match[T]{
+			Score: Dot(query, entry.Vector),
+			Value: entry.Value,
+		})
+	}
+
+	if candidates == nil {
+		for i := range entries {
+			add(&entries[i])
+		}
+	} else {
+		for _, i := range candidates {
+			add(&entries[i])
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches[:min(limit, len(matches))]
+}
+
+func withSameLength[T any](entries []Entry[T]) []Entry[T] {
+	result := make([]Entry[T], 0, len(entries))
+	for _, e := range entries {
+		if len(e.Vector) > 0 && (len(result) == 0 || len(e.Vector) == len(result[0].Vector)) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
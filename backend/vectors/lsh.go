@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package vectors
+
+import (
+	"math/rand/v2"
+)
+
+const (
+	// lshBits is the number of random hyperplanes per table.
+	lshBits = 16
+	// lshTables is the number of hash tables.
+	lshTables = 8
+)
+
+// LSHIndex is an approximate index with locality-sensitive hashing:
+// similar vectors are on the same side of most random hyperplanes, so
+// only entries in the same or a neighbouring bucket are compared.
+// Results can miss some matches, but it is much faster for large sets.
+type LSHIndex[T any] struct {
+	entries []Entry[T]
+	tables  []lshTable
+}
+
+type lshTable struct {
+	buckets map[uint32][]int
+	planes  [][]float32
+}
+
+// NewLSHIndex creates a new LSHIndex. Entries with another length than
+// the first one are ignored. The same seed creates the same hyperplanes.
+func NewLSHIndex[T any](entries []Entry[T], seed uint64) *LSHIndex[T] {
+	index := &LSHIndex[T]{
+		entries: withSameLength(entries),
+	}
+	if len(index.entries) == 0 {
+		return index
+	}
+
+	dimensions := len(index.entries[0].Vector)
+	random := rand.New(rand.NewPCG(seed, uint64(dimensions)))
+
+	index.tables = make([]lshTable, lshTables)
+	for t := range index.tables {
+		table := &index.tables[t]
+
+		table.planes = make([][]float32, lshBits)
+		for p := range table.planes {
+			plane := make([]float32, dimensions)
+			for i := range plane {
+				plane[i] = float32(random.NormFloat64())
+			}
+			table.planes[p] = plane
+		}
+
+		table.buckets = map[uint32][]int{}
+		for i, e := range index.entries {
+			key := table.hash(e.Vector)
+			table.buckets[key] = append(table.buckets[key], i)
+		}
+	}
+
+	return index
+}
+
+// Search implements the method of Index interface.
+// If there are not enough candidates, all entries are compared.
+func (index *LSHIndex[T]) Search(query []float32, limit int) []Match[T] {
+	if len(index.entries) == 0 || len(query) != len(index.entries[0].Vector) {
+		return make([]Match[T], 0)
+	}
+
+	seen := make(map[int]bool)
+	candidates := make([]int, 0)
+	for t := range index.tables {
+		table := &index.tables[t]
+		key := table.hash(query)
+
+		// the bucket and all buckets, which differ in one bit
+		for bit := -1; bit < lshBits; bit++ {
+			probe := key
+			if bit >= 0 {
+				probe ^= 1 << bit
+			}
+
+			for _, i := range table.buckets[probe] {
+				if !seen[i] {
+					seen[i] = true
+					candidates = append(candidates, i)
+				}
+			}
+		}
+	}
+
+	if len(candidates) < limit {
+		candidates = nil
+	}
+
+	return searchEntries(index.entries, candidates, query, limit)
+}
+
+// Size implements the method of Index interface.
+func (index *LSHIndex[T]) Size() int {
+	return len(index.entries)
+}
+
+func (table *lshTable) hash(vector []float32) uint32 {
+	var key uint32
+	for i, plane := range table.planes {
+		if Dot(plane, vector) >= 0 {
+			key |= 1 << i
+		}
+	}
+	return key
+}
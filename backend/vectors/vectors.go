@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package vectors stores embedding vectors and finds the most similar
+// ones by cosine similarity.
+package vectors
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Entry is a vector with a value, like the name of an image.
+type Entry[T any] struct {
+	// Value stores the value, which is returned with a Match.
+	Value T
+	// Vector stores the normalized vector.
+	Vector []float32
+}
+
+// Index finds the entries, which are the most similar to a vector.
+type Index[T any] interface {
+	// Search returns up to limit entries with the highest similarity
+	// to the normalized vector query, the most similar first.
+	Search(query []float32, limit int) []Match[T]
+	// Size returns the number of entries.
+	Size() int
+}
+
+// Match is a result of Index.Search().
+type Match[T any] struct {
+	// Score stores the cosine similarity between -1 and 1.
+	Score float32
+	// Value stores the value of the entry.
+	Value T
+}
+
+// Decode converts data created by Encode() back to a vector.
+func Decode(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid vector data with %d bytes", len(data))
+	}
+
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+
+	return vector, nil
+}
+
+// Dot returns the dot product of two vectors with the same length,
+// which is the cosine similarity of normalized vectors.
+func Dot(a []float32, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// Encode converts a vector to little endian float32 values.
+func Encode(vector []float32) []byte {
+	data := make([]byte, len(vector)*4)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+	}
+	return data
+}
+
+// Normalize scales a vector to length 1 in place and returns it.
+// A vector with length 0 is returned unchanged.
+func Normalize(vector []float32) []float32 {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return vector
+	}
+
+	length := float32(math.Sqrt(sum))
+	for i := range vector {
+		vector[i] /= length
+	}
+	return vector
+}
@@ -0,0 +1,258 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vectors
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// clusteredEntries returns count normalized vectors, which are near
+// to some random centers, like embeddings of similar images.
+func clusteredEntries(random *rand.Rand, count int, clusters int, dimensions int, noise float64) []Entry[int] {
+	centers := make([][]float32, clusters)
+	for i := range centers {
+		centers[i] = randomVector(random, dimensions, nil, 1)
+	}
+
+	entries := make([]Entry[int], count)
+	for i := range entries {
+		entries[i] = Entry[int]{
+			Value:  i,
+			Vector: randomVector(random, dimensions, centers[random.IntN(clusters)], noise),
+		}
+	}
+
+	return entries
+}
+
+// randomVector returns a normalized vector near center or a random one,
+// if center is nil.
+func randomVector(random *rand.Rand, dimensions int, center []float32, noise float64) []float32 {
+	vector := make([]float32, dimensions)
+	for i := range vector {
+		vector[i] = float32(random.NormFloat64() * noise / math.Sqrt(float64(dimensions)))
+		if center != nil {
+			vector[i] += center[i]
+		}
+	}
+
+	return Normalize(vector)
+}
+
+func TestExactIndexSearch(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+	entries := clusteredEntries(random, 300, 10, 32, 0.5)
+	index := NewExactIndex(entries)
+
+	tests := []struct {
+		name     string
+		query    []float32
+		limit    int
+		expected int
+	}{
+		{name: "limit", query: entries[0].Vector, limit: 10, expected: 10},
+		{name: "more than size", query: entries[0].Vector, limit: 1000, expected: 300},
+		{name: "no limit", query: entries[0].Vector, limit: 0, expected: 0},
+		{name: "other length", query: make([]float32, 16), limit: 10, expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := index.Search(test.query, test.limit)
+			if len(matches) != test.expected {
+				t.Fatalf("got %d matches, expected %d", len(matches), test.expected)
+			}
+
+			// compare with sorted scores of all entries
+			scores := make([]float32, len(entries))
+			for i, e := range entries {
+				scores[i] = Dot(test.query, e.Vector)
+			}
+			sort.Slice(scores, func(i, j int) bool {
+				return scores[i] > scores[j]
+			})
+
+			for i, m := range matches {
+				if m.Score != scores[i] || m.Score != Dot(test.query, entries[m.Value].Vector) {
+					t.Fatalf("got score %v at %d, expected %v", m.Score, i, scores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLSHIndexRecall(t *testing.T) {
+	random := rand.New(rand.NewPCG(3, 4))
+
+	tests := []struct {
+		name      string
+		entries   []Entry[int]
+		limit     int
+		minRecall float64
+	}{
+		// the nearest entries have a cosine similarity of about 0.9
+		{name: "clusters", entries: clusteredEntries(random, 5000, 100, 128, 0.3), limit: 10, minRecall: 0.9},
+		// about 0.8, where more matches are in other buckets
+		{name: "wide clusters", entries: clusteredEntries(random, 5000, 100, 128, 0.5), limit: 10, minRecall: 0.6},
+		{name: "tight clusters", entries: clusteredEntries(random, 5000, 500, 64, 0.2), limit: 5, minRecall: 0.95},
+		{name: "few entries", entries: clusteredEntries(random, 20, 2, 64, 1), limit: 10, minRecall: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exact := NewExactIndex(test.entries)
+			lsh := NewLSHIndex(test.entries, 42)
+			if lsh.Size() != exact.Size() {
+				t.Fatalf("got size %d, expected %d", lsh.Size(), exact.Size())
+			}
+
+			found, total := 0, 0
+			for i := 0; i < 200; i++ {
+				// a new vector near an existing one
+				query := randomVector(random, len(test.entries[0].Vector), test.entries[random.IntN(len(test.entries))].Vector, 0.3)
+
+				expected := map[int]bool{}
+				for _, m := range exact.Search(query, test.limit) {
+					expected[m.Value] = true
+				}
+
+				matches := lsh.Search(query, test.limit)
+				if len(matches) != len(expected) {
+					t.Fatalf("got %d matches, expected %d", len(matches), len(expected))
+				}
+				for _, m := range matches {
+					if expected[m.Value] {
+						found++
+					}
+				}
+				total += len(expected)
+			}
+
+			recall := float64(found) / float64(total)
+			if recall < test.minRecall {
+				t.Errorf("got recall %.3f, expected at least %.3f", recall, test.minRecall)
+			}
+		})
+	}
+}
+
+func TestLSHIndexSameSeed(t *testing.T) {
+	random := rand.New(rand.NewPCG(5, 6))
+	entries := clusteredEntries(random, 1000, 20, 32, 0.5)
+	query := randomVector(random, 32, nil, 1)
+
+	a := NewLSHIndex(entries, 7).Search(query, 10)
+	b := NewLSHIndex(entries, 7).Search(query, 10)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("got %v and %v with the same seed", a, b)
+	}
+}
+
+func TestIndexesIgnoreOtherLengths(t *testing.T) {
+	entries := []Entry[string]{
+		{Value: "empty"},
+		{Value: "a", Vector: []float32{1, 0}},
+		{Value: "b", Vector: []float32{1, 0, 0}},
+		{Value: "c", Vector: []float32{0, 1}},
+	}
+
+	for name, index := range map[string]Index[string]{
+		"exact": NewExactIndex(entries),
+		"lsh":   NewLSHIndex(entries, 1),
+	} {
+		if index.Size() != 2 {
+			t.Errorf("%s: got size %d, expected 2", name, index.Size())
+		}
+
+		matches := index.Search([]float32{1, 0}, 10)
+		expected := []Match[string]{{Score: 1, Value: "a"}, {Score: 0, Value: "c"}}
+		if !reflect.DeepEqual(matches, expected) {
+			t.Errorf("%s: got %v, expected %v", name, matches, expected)
+		}
+	}
+
+	for name, index := range map[string]Index[string]{
+		"exact": NewExactIndex[string](nil),
+		"lsh":   NewLSHIndex[string](nil, 1),
+	} {
+		if matches := index.Search([]float32{1, 0}, 10); len(matches) != 0 {
+			t.Errorf("%s: got %v from empty index", name, matches)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		expected    []float32
+		expectError bool
+	}{
+		{name: "empty", data: []byte{}, expected: []float32{}},
+		{name: "values", data: Encode([]float32{1, -0.5, float32(math.Inf(1))}), expected: []float32{1, -0.5, float32(math.Inf(1))}},
+		{name: "little endian", data: []byte{0, 0, 0x80, 0x3F}, expected: []float32{1}},
+		{name: "invalid length", data: []byte{0, 0, 0x80}, expectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vector, err := Decode(test.data)
+			if test.expectError {
+				if err == nil {
+					t.Errorf("expected error, got %v", vector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(vector, test.expected) {
+				t.Errorf("got %v, expected %v", vector, test.expected)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		vector   []float32
+		expected []float32
+	}{
+		{vector: []float32{3, 4}, expected: []float32{0.6, 0.8}},
+		{vector: []float32{0, -2, 0}, expected: []float32{0, -1, 0}},
+		{vector: []float32{0, 0}, expected: []float32{0, 0}},
+		{vector: []float32{}, expected: []float32{}},
+	}
+
+	for _, test := range tests {
+		vector := Normalize(append([]float32{}, test.vector...))
+		if !reflect.DeepEqual(vector, test.expected) {
+			t.Errorf("got %v for %v, expected %v", vector, test.vector, test.expected)
+		}
+	}
+}
@@ -140,7 +140,7 @@ const ImageCard: React.FC<ImageCardProps> = ({
           }
           title={image.info?.title || image.name || undefined}
           loading="lazy"
-          src={image.url}
+          src={image.thumbnail_url || image.url}
           onClick={onImageClick}
         />
 
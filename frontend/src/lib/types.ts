@@ -24,6 +24,51 @@
  * An image entry from the API.
  */
 export type ApiImage = {
+  /**
+   * EXIF and XMP metadata of the file, if there is some.
+   */
+  exif?: {
+    camera_make?: string;
+    camera_model?: string;
+    /**
+     * Exposure time in seconds.
+     */
+    exposure_time?: number;
+    f_number?: number;
+    /**
+     * Focal length in millimeters.
+     */
+    focal_length?: number;
+    gps?: {
+      altitude?: number;
+      latitude: number;
+      longitude: number;
+    };
+    iso?: number;
+    lens_model?: string;
+    /**
+     * Nearest city, if reverse geocoding is enabled.
+     */
+    location?: {
+      city: string;
+      country: string;
+      country_code: string;
+      region?: string;
+    };
+    orientation?: number;
+    /**
+     * Local time like `2024-07-01T14:03:22`, with offset, if known.
+     */
+    taken_at?: string;
+  };
+  /**
+   * File size in bytes.
+   */
+  filesize?: number;
+  /**
+   * Height in pixels, if known.
+   */
+  height?: number;
   /**
    * Optional information.
    */
@@ -32,6 +77,22 @@ export type ApiImage = {
      * Descriptions.
      */
     description: string;
+    /**
+     * Fields, which are protected from the AI.
+     */
+    locks?: {
+      description: boolean;
+      tags: boolean;
+      title: boolean;
+    };
+    /**
+     * Who wrote each field.
+     */
+    sources?: {
+      description?: MetaSource;
+      tags?: MetaSource;
+      title?: MetaSource;
+    };
     /**
      * List of tags.
      */
@@ -41,16 +102,41 @@ export type ApiImage = {
      */
     title: string;
   } | null;
+  /**
+   * MIME type of the file, like `image/jpeg`.
+   */
+  mime_type?: string;
+  /**
+   * Last modification time of the file.
+   */
+  modified?: string;
   /**
    * File name.
    */
   name: string;
+  /**
+   * Status of the AI meta data.
+   */
+  status: "fresh" | "stale" | "untagged";
+  /**
+   * URL of the thumbnail.
+   */
+  thumbnail_url?: string;
   /**
    * URL.
    */
   url: string;
+  /**
+   * Width in pixels, if known.
+   */
+  width?: number;
 };
 
+/**
+ * Who wrote a field of the meta data: the AI, a user or, for tags, both.
+ */
+export type MetaSource = "ai" | "manual" | "mixed";
+
 /**
  * An entry for the gallery.
  */